@@ -2,11 +2,14 @@
 package assfs
 
 import (
+	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
 )
 
 // AssertFs contains filesystem operations with asserts.
@@ -90,3 +93,49 @@ func (v *AssertFs) Contains(filep, content, message string) {
 func (v *AssertFs) WriteBytes(filep string, b []byte) error {
 	return v.fs.WriteFile(filep, b, 0600)
 }
+
+// WriteTree writes all files, creating directories as needed.
+// Keys of files are filepaths and values their contents.
+func (v *AssertFs) WriteTree(files map[string]string, message string) {
+	for filep, content := range files {
+		v.WriteTextFile(filep, content, message)
+	}
+}
+
+// TreeListing returns sorted filepaths of all files under rootp.
+// Filepaths are relative to rootp and use forward slashes.
+func (v *AssertFs) TreeListing(rootp string) []string {
+	files := []string{}
+	err := v.fs.Walk(rootp, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		relp, err := filepath.Rel(rootp, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, filepath.ToSlash(relp))
+		return nil
+	})
+	v.req.Nilf(err, "tree listing, path: %s, error: %s", rootp, err)
+	sort.Strings(files)
+	return files
+}
+
+// AssertYAML asserts that YAML file filep is semantically equal to expected.
+// Expected is converted to YAML and back so that it's comparable with the file's content,
+// and key order or formatting don't matter.
+func (v *AssertFs) AssertYAML(filep string, expected any, message string) {
+	b, err := v.fs.ReadFile(filep)
+	v.req.Nilf(err, "read yaml, path: %s, message: %s", filep, message)
+	var actual any
+	err = yaml.Unmarshal(b, &actual)
+	v.req.Nilf(err, "unmarshal yaml, path: %s, message: %s, error: %s", filep, message, err)
+
+	b, err = yaml.Marshal(expected)
+	v.req.Nilf(err, "marshal expected, path: %s, message: %s, error: %s", filep, message, err)
+	var normalized any
+	err = yaml.Unmarshal(b, &normalized)
+	v.req.Nilf(err, "unmarshal expected, path: %s, message: %s, error: %s", filep, message, err)
+	v.req.Equalf(normalized, actual, "yaml, path: %s, message: %s", filep, message)
+}
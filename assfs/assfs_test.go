@@ -0,0 +1,67 @@
+package assfs
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func newAssertFs(t *testing.T) *AssertFs {
+	return NewAssertFs(require.New(t), &afero.Afero{Fs: afero.NewMemMapFs()})
+}
+
+func TestWriteTree(t *testing.T) {
+	ass := newAssertFs(t)
+	ass.WriteTree(
+		map[string]string{
+			"/root/a.txt":         "a",
+			"/root/sub/b.txt":     "b",
+			"/root/sub/deep/c.md": "c",
+		},
+		"tree")
+	ass.DirExists("/root/sub/deep", "deepest dir")
+	ass.Contains("/root/a.txt", "a", "a")
+	ass.Contains("/root/sub/b.txt", "b", "b")
+	ass.Contains("/root/sub/deep/c.md", "c", "c")
+}
+
+func TestTreeListing(t *testing.T) {
+	ass := newAssertFs(t)
+	ass.WriteTree(
+		map[string]string{
+			"/root/z.txt":          "",
+			"/root/sub/b.txt":      "",
+			"/root/sub/deep/a.txt": "",
+			"/other/x.txt":         "",
+		},
+		"tree")
+	ass.MkdirAll("/root/empty", "empty dir")
+	require.Equal(
+		t,
+		[]string{"sub/b.txt", "sub/deep/a.txt", "z.txt"},
+		ass.TreeListing("/root"))
+}
+
+func TestAssertYAML(t *testing.T) {
+	type server struct {
+		Host  string   `yaml:"host"`
+		Port  int      `yaml:"port"`
+		Tags  []string `yaml:"tags"`
+		Debug bool     `yaml:"debug"`
+	}
+	ass := newAssertFs(t)
+	ass.WriteTree(
+		map[string]string{
+			"/block.yaml": "host: localhost\nport: 8080\ntags:\n  - a\n  - b\ndebug: true\n",
+			"/flow.yaml":  "{debug: true, tags: [a, b], port: 8080, host: 'localhost'}",
+		},
+		"yaml")
+	expected := server{Host: "localhost", Port: 8080, Tags: []string{"a", "b"}, Debug: true}
+	ass.AssertYAML("/block.yaml", expected, "block")
+	ass.AssertYAML("/flow.yaml", expected, "flow")
+	ass.AssertYAML(
+		"/flow.yaml",
+		map[string]any{"host": "localhost", "port": 8080, "tags": []string{"a", "b"}, "debug": true},
+		"map")
+}
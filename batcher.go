@@ -0,0 +1,149 @@
+package gent
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Batcher accumulates items and passes them to a flush function in batches.
+// A batch is flushed when it reaches the size threshold or when the interval elapses,
+// whichever happens first.
+// The flush function may call Add and Flush.
+type Batcher[T any] struct {
+	// Guards items.
+	mu    sync.Mutex
+	items []T
+	// Held while items are passed to flush, keeps the batches in order.
+	flushMu sync.Mutex
+	size    int
+	flush   func(items []T)
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// NewBatcher creates a [gent.Batcher].
+// Non-positive size disables the size trigger and non-positive interval the time trigger.
+// Interval is measured with clock, nil clock defaults to [gent.SystemClock].
+// Call [gent.Batcher.Close] when done to flush the remaining items.
+func NewBatcher[T any](
+	size int,
+	interval time.Duration,
+	clock Clock,
+	flush func(items []T),
+) *Batcher[T] {
+	batcher := &Batcher[T]{
+		size:  size,
+		flush: flush,
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+	if interval > 0 {
+		go batcher.tick(interval, Tri[Clock](clock == nil, SystemClock{}, clock))
+	} else {
+		close(batcher.done)
+	}
+	return batcher
+}
+
+func (v *Batcher[T]) tick(interval time.Duration, clock Clock) {
+	defer close(v.done)
+	for {
+		select {
+		case <-clock.After(interval):
+			v.Flush()
+		case <-v.stop:
+			return
+		}
+	}
+}
+
+// Add an item to the batch, flush if the batch is full.
+func (v *Batcher[T]) Add(item T) {
+	v.mu.Lock()
+	v.items = append(v.items, item)
+	full := v.size > 0 && len(v.items) >= v.size
+	v.mu.Unlock()
+	if full {
+		v.Flush()
+	}
+}
+
+// Flush passes the pending items to the flush function.
+// Nothing is done when there are no pending items.
+// When a flush is already in progress, e.g. Flush is called from the flush function,
+// the pending items are passed by it once it's done with the current batch.
+func (v *Batcher[T]) Flush() {
+	for v.flushMu.TryLock() {
+		for items := v.take(); len(items) > 0; items = v.take() {
+			v.flush(items)
+		}
+		v.flushMu.Unlock()
+		// Items added after the last take, while flushMu was still held, would be left behind.
+		v.mu.Lock()
+		empty := len(v.items) == 0
+		v.mu.Unlock()
+		if empty {
+			return
+		}
+	}
+}
+
+func (v *Batcher[T]) take() []T {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	items := v.items
+	v.items = nil
+	return items
+}
+
+// Close stops the time trigger and flushes the pending items.
+// Batcher must not be used after it's closed.
+func (v *Batcher[T]) Close() {
+	close(v.stop)
+	<-v.done
+	v.Flush()
+}
+
+// Batch reads items from in and emits them in batches of size items.
+// When in is closed, the last partial batch is emitted and the returned channel is closed.
+// Panics when size is less than 1.
+func Batch[T any](in <-chan T, size int) <-chan []T {
+	if size < 1 {
+		panic(fmt.Sprintf("Invalid batch size: %d.", size))
+	}
+	out := make(chan []T)
+	go func() {
+		defer close(out)
+		batch := make([]T, 0, size)
+		for each := range in {
+			batch = append(batch, each)
+			if len(batch) == size {
+				out <- batch
+				batch = make([]T, 0, size)
+			}
+		}
+		if len(batch) > 0 {
+			out <- batch
+		}
+	}()
+	return out
+}
+
+// StreamContext sends the items of s to the returned channel and closes it.
+// Sending stops early, and the channel is closed, when ctx is cancelled.
+func StreamContext[T any](ctx context.Context, s []T) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for _, each := range s {
+			select {
+			case out <- each:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
@@ -0,0 +1,136 @@
+package gent
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClock struct {
+	now   time.Time
+	after chan time.Time
+}
+
+func (v *fakeClock) Now() time.Time {
+	return v.now
+}
+
+func (v *fakeClock) After(_ time.Duration) <-chan time.Time {
+	return v.after
+}
+
+func TestBatcher(t *testing.T) {
+	t.Run("size", func(t *testing.T) {
+		req := require.New(t)
+		var batches [][]int
+		batcher := NewBatcher(2, 0, nil, func(items []int) {
+			batches = append(batches, items)
+		})
+		for i := 1; i <= 5; i++ {
+			batcher.Add(i)
+		}
+		req.Equal([][]int{{1, 2}, {3, 4}}, batches)
+		batcher.Close()
+		req.Equal([][]int{{1, 2}, {3, 4}, {5}}, batches, "close flushes the rest")
+	})
+
+	t.Run("close without items", func(t *testing.T) {
+		flushed := false
+		batcher := NewBatcher(2, 0, nil, func(_ []string) { flushed = true })
+		batcher.Close()
+		require.False(t, flushed)
+	})
+
+	t.Run("interval", func(t *testing.T) {
+		req := require.New(t)
+		clock := &fakeClock{after: make(chan time.Time)}
+		flushed := make(chan []string, 1)
+		batcher := NewBatcher(10, time.Minute, clock, func(items []string) {
+			flushed <- items
+		})
+		batcher.Add("a")
+		batcher.Add("b")
+		clock.after <- clock.now
+		req.Equal([]string{"a", "b"}, <-flushed)
+		batcher.Close()
+		req.Empty(flushed, "nothing left to flush")
+	})
+
+	t.Run("flush adds", func(t *testing.T) {
+		req := require.New(t)
+		var batcher *Batcher[int]
+		var batches [][]int
+		batcher = NewBatcher(2, 0, nil, func(items []int) {
+			batches = append(batches, items)
+			if items[0] < 10 {
+				for _, each := range items {
+					batcher.Add(each * 10)
+				}
+				batcher.Flush()
+			}
+		})
+		batcher.Add(1)
+		batcher.Add(2)
+		batcher.Add(3)
+		batcher.Close()
+		req.Equal([][]int{{1, 2}, {10, 20}, {3}, {30}}, batches)
+	})
+
+	t.Run("interval without clock", func(t *testing.T) {
+		var batches [][]int
+		batcher := NewBatcher(10, time.Hour, nil, func(items []int) {
+			batches = append(batches, items)
+		})
+		batcher.Add(1)
+		batcher.Close()
+		require.Equal(t, [][]int{{1}}, batches)
+	})
+}
+
+func TestBatch(t *testing.T) {
+	req := require.New(t)
+	in := make(chan int)
+	go func() {
+		defer close(in)
+		for i := 1; i <= 5; i++ {
+			in <- i
+		}
+	}()
+	var batches [][]int
+	for each := range Batch(in, 2) {
+		batches = append(batches, each)
+	}
+	req.Equal([][]int{{1, 2}, {3, 4}, {5}}, batches)
+
+	empty := make(chan int)
+	close(empty)
+	_, ok := <-Batch(empty, 2)
+	req.False(ok, "closed without batches")
+	req.PanicsWithValue("Invalid batch size: 0.", func() { Batch(empty, 0) })
+}
+
+func TestStreamContext(t *testing.T) {
+	t.Run("drain", func(t *testing.T) {
+		var received []int
+		for each := range StreamContext(context.Background(), []int{1, 2, 3}) {
+			received = append(received, each)
+		}
+		require.Equal(t, []int{1, 2, 3}, received)
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		req := require.New(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		ch := StreamContext(ctx, []int{1, 2, 3, 4, 5})
+		req.Equal(1, <-ch)
+		cancel()
+		received := 0
+		for range ch {
+			received++
+		}
+		// A single send may already have been selected alongside cancellation.
+		req.LessOrEqual(received, 1)
+	})
+}
@@ -0,0 +1,23 @@
+package gent
+
+import "time"
+
+// Clock provides the current time and timers.
+// Inject it into time dependent code so that tests don't have to sleep.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// SystemClock is a [gent.Clock] backed by the time package.
+type SystemClock struct{}
+
+// Now returns the current local time.
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// After waits for the duration to elapse and then sends the current time on the channel.
+func (SystemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
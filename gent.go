@@ -3,8 +3,16 @@ package gent
 
 import (
 	"bufio"
+	"cmp"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"iter"
 	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
 )
 
 // Pair is a pair of values.
@@ -18,7 +26,36 @@ func NewPair[T any, U any](first T, second U) Pair[T, U] {
 	return Pair[T, U]{First: first, Second: second}
 }
 
+// Swap returns a new pair with First and Second swapped.
+func (v Pair[T, U]) Swap() Pair[U, T] {
+	return NewPair(v.Second, v.First)
+}
+
+// PairToSlice returns the values of a homogeneous pair in a slice.
+func PairToSlice[T any](p Pair[T, T]) []T {
+	return []T{p.First, p.Second}
+}
+
+// PairsFromChannels zips the values received from a and b into pairs until either closes.
+// A value received from a is dropped when b turns out to be closed.
+func PairsFromChannels[T, U any](a <-chan T, b <-chan U) []Pair[T, U] {
+	var pairs []Pair[T, U]
+	for {
+		first, ok := <-a
+		if !ok {
+			return pairs
+		}
+		second, ok := <-b
+		if !ok {
+			return pairs
+		}
+		pairs = append(pairs, NewPair(first, second))
+	}
+}
+
 // Set is a naive map backed set.
+// Nil *Set behaves as an empty set in read operations, e.g. [gent.Set.Has] and [gent.Set.Len].
+// Zero value Set is an empty set that's ready to use.
 type Set[T comparable] struct {
 	m map[T]bool
 }
@@ -32,9 +69,35 @@ func NewSet[T comparable](items ...T) *Set[T] {
 	return set
 }
 
+// NewSetFromSlice creates a new [gent.Set] from the items in s.
+func NewSetFromSlice[T comparable](s []T) *Set[T] {
+	return NewSet(s...)
+}
+
+// NewSetFromKeys creates a new [gent.Set] from the keys of m.
+func NewSetFromKeys[K comparable, V any](m map[K]V) *Set[K] {
+	set := &Set[K]{m: make(map[K]bool, len(m))}
+	for key := range m {
+		set.Add(key)
+	}
+	return set
+}
+
+// NewSetFromSeq creates a new [gent.Set] from the items of seq.
+func NewSetFromSeq[T comparable](seq iter.Seq[T]) *Set[T] {
+	set := NewSet[T]()
+	for each := range seq {
+		set.Add(each)
+	}
+	return set
+}
+
 // Add item to the set, return true if it was added.
 // Otherwise it already existed and wasn't added.
 func (v *Set[T]) Add(item T) (added bool) {
+	if v.m == nil {
+		v.m = map[T]bool{}
+	}
 	_, existed := v.m[item]
 	if existed {
 		return
@@ -44,16 +107,44 @@ func (v *Set[T]) Add(item T) (added bool) {
 	return
 }
 
+// AddIf adds item to the set only when cond is true, return true if it was added.
+func (v *Set[T]) AddIf(item T, cond bool) bool {
+	return cond && v.Add(item)
+}
+
+// AddAll adds items to the set and returns how many of them were added.
+// Items that already existed or were repeated in items aren't counted.
+func (v *Set[T]) AddAll(items ...T) (addedCount int) {
+	return v.AddSlice(items)
+}
+
+// AddSlice is like [gent.Set.AddAll] but takes a slice.
+func (v *Set[T]) AddSlice(items []T) (addedCount int) {
+	for _, each := range items {
+		if v.Add(each) {
+			addedCount++
+		}
+	}
+	return
+}
+
 // Clear the set, remove all items.
-func (v *Set[T]) Clear() {
+// Returns the number of removed items.
+func (v *Set[T]) Clear() (removed int) {
+	removed = len(v.m)
 	v.m = map[T]bool{}
+	return
 }
 
 // Equal returns true when the sets contain the exact same items.
+// Nil set is equal to an empty set.
 func (v *Set[T]) Equal(s *Set[T]) bool {
-	if len(v.m) != s.Len() {
+	if v.Len() != s.Len() {
 		return false
 	}
+	if v == nil {
+		return true
+	}
 	for each := range v.m {
 		if !s.Has(each) {
 			return false
@@ -62,6 +153,57 @@ func (v *Set[T]) Equal(s *Set[T]) bool {
 	return true
 }
 
+// EqualDiff is like [gent.Set.Equal] but also describes the difference.
+// Missing contains items in this set but not in other,
+// and extra contains items in other but not in this set.
+func (v *Set[T]) EqualDiff(other *Set[T]) (equal bool, missing, extra []T) {
+	missing = v.Difference(other).ToSlice()
+	extra = other.Difference(v).ToSlice()
+	equal = len(missing) == 0 && len(extra) == 0
+	return
+}
+
+// IsSubset returns true when every item in this set exists in other.
+// Empty set is a subset of every set.
+func (v *Set[T]) IsSubset(other *Set[T]) bool {
+	if v.Len() > other.Len() {
+		return false
+	}
+	if v == nil {
+		return true
+	}
+	for each := range v.m {
+		if !other.Has(each) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSuperset returns true when every item in other exists in this set.
+// Every set is a superset of the empty set.
+func (v *Set[T]) IsSuperset(other *Set[T]) bool {
+	return other.IsSubset(v)
+}
+
+// TestingT is the part of [testing.T] that [gent.Set.AssertEqual] uses.
+type TestingT interface {
+	Errorf(format string, args ...any)
+	FailNow()
+}
+
+// AssertEqual fails test t when the sets differ, listing the items that differ.
+// Missing items are in this set but not in other and extra ones are in other but not in this set.
+func (v *Set[T]) AssertEqual(t TestingT, other *Set[T], msg string) {
+	if h, ok := t.(interface{ Helper() }); ok {
+		h.Helper()
+	}
+	if equal, missing, extra := v.EqualDiff(other); !equal {
+		t.Errorf("Sets differ, missing: %v, extra: %v. %s", missing, extra, msg)
+		t.FailNow()
+	}
+}
+
 // Contains checks if item exists in the set.
 // Alias for [gent.Set.Has].
 func (v *Set[T]) Contains(item T) bool {
@@ -70,6 +212,9 @@ func (v *Set[T]) Contains(item T) bool {
 
 // Has checks if item exists in the set.
 func (v *Set[T]) Has(item T) bool {
+	if v == nil {
+		return false
+	}
 	_, ok := v.m[item]
 	return ok
 }
@@ -77,6 +222,9 @@ func (v *Set[T]) Has(item T) bool {
 // ForEach iterates all items in the set, calls f for each item, stops if stop is called.
 // Use [gent.ForEachAll] if there's no need to stop iteration.
 func (v *Set[T]) ForEach(f func(each T, stop func())) {
+	if v == nil {
+		return
+	}
 	breaker := false
 	for each := range v.m {
 		f(each, func() {
@@ -91,13 +239,45 @@ func (v *Set[T]) ForEach(f func(each T, stop func())) {
 // ForEachAll iterates all items in the set and calls f for each item.
 // Use [gent.ForEach] if you need to stop iteration.
 func (v *Set[T]) ForEachAll(f func(each T)) {
+	if v == nil {
+		return
+	}
 	for key := range v.m {
 		f(key)
 	}
 }
 
+// All returns an iterator over the items in the set, for use with range.
+// Order of the items is random.
+func (v *Set[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if v == nil {
+			return
+		}
+		for each := range v.m {
+			if !yield(each) {
+				return
+			}
+		}
+	}
+}
+
+// ForEachIndexed iterates all items in the set and calls f for each item with a running index.
+// Indexes run from 0 to Len-1 but as the iteration order is random,
+// the same item may get a different index in another call.
+func (v *Set[T]) ForEachIndexed(f func(i int, each T)) {
+	i := 0
+	v.ForEachAll(func(each T) {
+		f(i, each)
+		i++
+	})
+}
+
 // Len returns the number of items in the set.
 func (v *Set[T]) Len() int {
+	if v == nil {
+		return 0
+	}
 	return len(v.m)
 }
 
@@ -110,21 +290,567 @@ func (v *Set[T]) Count() int {
 // Remove removes an item in the set, returns true if it was.
 // I.e. if it existed.
 func (v *Set[T]) Remove(item T) (existed bool) {
+	if v == nil {
+		return
+	}
 	_, existed = v.m[item]
 	delete(v.m, item)
 	return
 }
 
+// Clone returns a new set with the same items.
+func (v *Set[T]) Clone() *Set[T] {
+	return NewSet(v.ToSlice()...)
+}
+
+// Pop removes and returns an arbitrary item from the set.
+// False is returned when the set is empty.
+func (v *Set[T]) Pop() (item T, ok bool) {
+	if v == nil {
+		return
+	}
+	for item = range v.m {
+		delete(v.m, item)
+		return item, true
+	}
+	return
+}
+
+// RemoveAll removes items from the set and returns how many of them were removed.
+// Items that didn't exist or were repeated in items aren't counted.
+func (v *Set[T]) RemoveAll(items ...T) (removedCount int) {
+	for _, each := range items {
+		if v.Remove(each) {
+			removedCount++
+		}
+	}
+	return
+}
+
+// With adds items to the set and returns the set itself for chaining.
+// Note that the set is mutated.
+func (v *Set[T]) With(items ...T) *Set[T] {
+	for _, each := range items {
+		v.Add(each)
+	}
+	return v
+}
+
+// Without removes items from the set and returns the set itself for chaining.
+// Note that the set is mutated.
+func (v *Set[T]) Without(items ...T) *Set[T] {
+	for _, each := range items {
+		v.Remove(each)
+	}
+	return v
+}
+
 // ToSlice returns a slice with all set items.
 // Set itself doesn't change.
 func (v *Set[T]) ToSlice() []T {
 	keys := []T{}
+	if v == nil {
+		return keys
+	}
 	for each := range v.m {
 		keys = append(keys, each)
 	}
 	return keys
 }
 
+// String formats the set like "Set{a, b, c}".
+// Items are formatted with [fmt.Sprint] and their order is unspecified.
+func (v *Set[T]) String() string {
+	return "Set{" + Join(v.ToSlice(), ", ") + "}"
+}
+
+// MarshalJSON marshals the set into a JSON array.
+// Order of the items is unspecified, use [gent.MarshalJSONSorted] for stable output.
+func (v *Set[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(v.ToSlice())
+}
+
+// UnmarshalJSON unmarshals a JSON array into the set, replacing any existing items.
+// Duplicates in the array are collapsed.
+func (v *Set[T]) UnmarshalJSON(b []byte) error {
+	var items []T
+	if err := json.Unmarshal(b, &items); err != nil {
+		return err
+	}
+	v.m = map[T]bool{}
+	v.AddSlice(items)
+	return nil
+}
+
+// Union returns a new set with the items that exist in either set.
+func (v *Set[T]) Union(other *Set[T]) *Set[T] {
+	result := NewSet(v.ToSlice()...)
+	other.ForEachAll(func(each T) {
+		result.Add(each)
+	})
+	return result
+}
+
+// Intersection returns a new set with the items that exist in both sets.
+func (v *Set[T]) Intersection(other *Set[T]) *Set[T] {
+	result := NewSet[T]()
+	v.ForEachAll(func(each T) {
+		if other.Has(each) {
+			result.Add(each)
+		}
+	})
+	return result
+}
+
+// Difference returns a new set with the items in this set that don't exist in other.
+func (v *Set[T]) Difference(other *Set[T]) *Set[T] {
+	result := NewSet[T]()
+	v.ForEachAll(func(each T) {
+		if !other.Has(each) {
+			result.Add(each)
+		}
+	})
+	return result
+}
+
+// SymmetricDifference returns a new set with the items that exist in exactly one of the sets.
+func (v *Set[T]) SymmetricDifference(other *Set[T]) *Set[T] {
+	result := v.Difference(other)
+	other.ForEachAll(func(each T) {
+		if !v.Has(each) {
+			result.Add(each)
+		}
+	})
+	return result
+}
+
+// IntersectSlice returns a new set with the items that exist both in this set and in items.
+func (v *Set[T]) IntersectSlice(items []T) *Set[T] {
+	result := NewSet[T]()
+	for _, each := range items {
+		if v.Has(each) {
+			result.Add(each)
+		}
+	}
+	return result
+}
+
+// UnionSorted returns items that exist in either set, sorted with compare.
+// Comparator compare is like in [slices.SortFunc].
+func (v *Set[T]) UnionSorted(other *Set[T], compare func(a, b T) int) []T {
+	return v.Union(other).toSortedSlice(compare)
+}
+
+// IntersectionSorted returns items that exist in both sets, sorted with compare.
+// Comparator compare is like in [slices.SortFunc].
+func (v *Set[T]) IntersectionSorted(other *Set[T], compare func(a, b T) int) []T {
+	return v.Intersection(other).toSortedSlice(compare)
+}
+
+// DifferenceSorted returns items that exist in this set but not in other, sorted with compare.
+// Comparator compare is like in [slices.SortFunc].
+func (v *Set[T]) DifferenceSorted(other *Set[T], compare func(a, b T) int) []T {
+	return v.Difference(other).toSortedSlice(compare)
+}
+
+// Filter returns a new set with the items for which f returns true.
+func (v *Set[T]) Filter(f func(T) bool) *Set[T] {
+	filtered := NewSet[T]()
+	v.ForEachAll(func(each T) {
+		if f(each) {
+			filtered.Add(each)
+		}
+	})
+	return filtered
+}
+
+// Diff compares the set as the old state to other as the new state.
+// Added contains items only in other and removed contains items only in this set.
+func (v *Set[T]) Diff(other *Set[T]) (added, removed *Set[T]) {
+	return other.Difference(v), v.Difference(other)
+}
+
+// PartitionInto splits the items into n roughly equally sized sets, e.g. for n workers.
+// Sizes of the sets differ at most by one and the distribution of items is unspecified.
+// Panics when n is less than 1.
+func (v *Set[T]) PartitionInto(n int) []*Set[T] {
+	if n < 1 {
+		panic(fmt.Sprintf("Invalid partition count: %d.", n))
+	}
+	partitions := make([]*Set[T], n)
+	for i := range partitions {
+		partitions[i] = NewSet[T]()
+	}
+	v.ForEachIndexed(func(i int, each T) {
+		partitions[i%n].Add(each)
+	})
+	return partitions
+}
+
+// MaxSubsetsLen is the largest set [gent.Set.Subsets] accepts.
+const MaxSubsetsLen = 20
+
+// Subsets returns all subsets of the set, i.e. the power set, including empty and full sets.
+// There are 2^Len subsets so it panics when the set has more than [gent.MaxSubsetsLen] items.
+func (v *Set[T]) Subsets() []*Set[T] {
+	items := v.ToSlice()
+	if len(items) > MaxSubsetsLen {
+		panic(fmt.Sprintf("Too many items for subsets: %d > %d.", len(items), MaxSubsetsLen))
+	}
+	subsets := make([]*Set[T], 0, 1<<len(items))
+	for mask := 0; mask < 1<<len(items); mask++ {
+		subset := NewSet[T]()
+		for i, each := range items {
+			if mask&(1<<i) != 0 {
+				subset.Add(each)
+			}
+		}
+		subsets = append(subsets, subset)
+	}
+	return subsets
+}
+
+func (v *Set[T]) toSortedSlice(compare func(a, b T) int) []T {
+	items := v.ToSlice()
+	slices.SortFunc(items, compare)
+	return items
+}
+
+// MapSet maps the items of s into a new set.
+// Items that map to the same value collapse into one, i.e. the new set may be smaller than s.
+func MapSet[T, U comparable](s *Set[T], f func(T) U) *Set[U] {
+	mapped := NewSet[U]()
+	s.ForEachAll(func(each T) {
+		mapped.Add(f(each))
+	})
+	return mapped
+}
+
+// MapSetMerge maps the items of s into a new set.
+// Items that map to the same value are merged, i.e. the new set may be smaller than s.
+// Use [gent.MapSetCount] to find out how many items were merged.
+// Alias for [gent.MapSet].
+func MapSetMerge[T, U comparable](s *Set[T], f func(T) U) *Set[U] {
+	return MapSet(s, f)
+}
+
+// MapSetCount maps the items of s and counts how many items mapped to each value.
+func MapSetCount[T, U comparable](s *Set[T], f func(T) U) map[U]int {
+	counts := map[U]int{}
+	s.ForEachAll(func(each T) {
+		counts[f(each)]++
+	})
+	return counts
+}
+
+// ToMap creates a map from the items of s to values computed with valueFn.
+func ToMap[T comparable, V any](s *Set[T], valueFn func(T) V) map[T]V {
+	m := make(map[T]V, s.Len())
+	s.ForEachAll(func(each T) {
+		m[each] = valueFn(each)
+	})
+	return m
+}
+
+// MarshalJSONSorted marshals s into a JSON array with the items in ascending order.
+// Unlike with map iteration, the output is stable which makes it suitable for golden files.
+func MarshalJSONSorted[T cmp.Ordered](s *Set[T]) ([]byte, error) {
+	items := s.ToSlice()
+	slices.Sort(items)
+	return json.Marshal(items)
+}
+
+// SliceContainsAll returns true when every item in needles exists in haystack.
+func SliceContainsAll[T comparable](haystack, needles []T) bool {
+	set := NewSet(haystack...)
+	for _, each := range needles {
+		if !set.Has(each) {
+			return false
+		}
+	}
+	return true
+}
+
+// SlicesIntersect returns true when a and b have at least one item in common.
+func SlicesIntersect[T comparable](a, b []T) bool {
+	set := NewSet(a...)
+	for _, each := range b {
+		if set.Has(each) {
+			return true
+		}
+	}
+	return false
+}
+
+// SliceDifference returns items in a that don't exist in b.
+// Order and duplicates of a are preserved.
+func SliceDifference[T comparable](a, b []T) []T {
+	excluded := NewSet(b...)
+	return Filter(a, func(each T) bool {
+		return !excluded.Has(each)
+	})
+}
+
+// Distinct returns s without duplicates, keeping the first occurrence of each item.
+func Distinct[T comparable](s []T) []T {
+	if s == nil {
+		return nil
+	}
+	seen := NewSet[T]()
+	return Filter(s, seen.Add)
+}
+
+// DedupConsecutive returns s without consecutive duplicate items, like uniq.
+// Repeating items that aren't adjacent are kept.
+func DedupConsecutive[T comparable](s []T) []T {
+	if s == nil {
+		return nil
+	}
+	deduped := []T{}
+	for i, each := range s {
+		if i == 0 || each != s[i-1] {
+			deduped = append(deduped, each)
+		}
+	}
+	return deduped
+}
+
+// GroupBy groups the items in s by the key returned by keyFn.
+// Items within each group are in the same order as in s.
+func GroupBy[T any, K comparable](s []T, keyFn func(T) K) map[K][]T {
+	groups := map[K][]T{}
+	for _, each := range s {
+		key := keyFn(each)
+		groups[key] = append(groups[key], each)
+	}
+	return groups
+}
+
+// CountBy counts how many items in s fall into each key returned by keyFn.
+func CountBy[T any, K comparable](s []T, keyFn func(T) K) map[K]int {
+	counts := map[K]int{}
+	for _, each := range s {
+		counts[keyFn(each)]++
+	}
+	return counts
+}
+
+// Keys returns the keys of m in unspecified order.
+func Keys[K comparable, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// Values returns the values of m in unspecified order.
+func Values[K comparable, V any](m map[K]V) []V {
+	values := make([]V, 0, len(m))
+	for _, each := range m {
+		values = append(values, each)
+	}
+	return values
+}
+
+// Entries returns the key-value pairs of m in unspecified order.
+func Entries[K comparable, V any](m map[K]V) []Pair[K, V] {
+	entries := make([]Pair[K, V], 0, len(m))
+	for key, each := range m {
+		entries = append(entries, NewPair(key, each))
+	}
+	return entries
+}
+
+// KeysWithValue returns all keys in m that map to value.
+// Order of the keys is unspecified.
+func KeysWithValue[K comparable, V comparable](m map[K]V, value V) []K {
+	keys := []K{}
+	for key, each := range m {
+		if each == value {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// MergeMaps returns a new map with the entries of both a and b.
+// For keys that exist in both, resolve decides the value.
+func MergeMaps[K comparable, V any](a, b map[K]V, resolve func(key K, av, bv V) V) map[K]V {
+	merged := make(map[K]V, len(a)+len(b))
+	for key, each := range a {
+		merged[key] = each
+	}
+	for key, each := range b {
+		if existing, ok := a[key]; ok {
+			merged[key] = resolve(key, existing, each)
+		} else {
+			merged[key] = each
+		}
+	}
+	return merged
+}
+
+// FilterMapEntries returns a new map with the entries of m for which pred returns true.
+func FilterMapEntries[K comparable, V any](m map[K]V, pred func(K, V) bool) map[K]V {
+	filtered := map[K]V{}
+	for key, each := range m {
+		if pred(key, each) {
+			filtered[key] = each
+		}
+	}
+	return filtered
+}
+
+// GetOrInsert returns the value of key in m.
+// When key is absent, the value is created with create and inserted into m first.
+func GetOrInsert[K comparable, V any](m map[K]V, key K, create func() V) V {
+	if value, ok := m[key]; ok {
+		return value
+	}
+	value := create()
+	m[key] = value
+	return value
+}
+
+// MovingAverage returns the averages of each window length span in s.
+// Length of the result is len(s)-window+1,
+// i.e. empty when window is larger than s or less than 1.
+func MovingAverage(s []float64, window int) []float64 {
+	if window < 1 || window > len(s) {
+		return []float64{}
+	}
+	averages := make([]float64, 0, len(s)-window+1)
+	var sum float64
+	for i, each := range s {
+		sum += each
+		if i >= window {
+			sum -= s[i-window]
+		}
+		if i >= window-1 {
+			averages = append(averages, sum/float64(window))
+		}
+	}
+	return averages
+}
+
+// Enumerate pairs each item in s with its index.
+func Enumerate[T any](s []T) []Pair[int, T] {
+	enumerated := make([]Pair[int, T], len(s))
+	for i, each := range s {
+		enumerated[i] = NewPair(i, each)
+	}
+	return enumerated
+}
+
+// Chunk splits s into chunks of size items, the last chunk may be shorter.
+// Chunks share the backing array with s.
+// Panics when size is less than 1.
+func Chunk[T any](s []T, size int) [][]T {
+	if size < 1 {
+		panic(fmt.Sprintf("Invalid chunk size: %d.", size))
+	}
+	chunks := make([][]T, 0, (len(s)+size-1)/size)
+	for start := 0; start < len(s); start += size {
+		end := min(start+size, len(s))
+		chunks = append(chunks, s[start:end:end])
+	}
+	return chunks
+}
+
+// Resize returns a copy of s with exactly n items.
+// Extra items are truncated and missing ones are filled with fill.
+func Resize[T any](s []T, n int, fill T) []T {
+	resized := make([]T, n)
+	copied := copy(resized, s)
+	for i := copied; i < n; i++ {
+		resized[i] = fill
+	}
+	return resized
+}
+
+// Reverse returns a reversed copy of s.
+func Reverse[T any](s []T) []T {
+	reversed := CloneSlice(s)
+	ReverseInPlace(reversed)
+	return reversed
+}
+
+// ReverseInPlace reverses s.
+func ReverseInPlace[T any](s []T) {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		s[i], s[j] = s[j], s[i]
+	}
+}
+
+// Rotate returns a copy of s rotated left by n positions, negative n rotates right.
+// N is taken modulo the length of s.
+func Rotate[T any](s []T, n int) []T {
+	if len(s) == 0 {
+		return CloneSlice(s)
+	}
+	n = ((n % len(s)) + len(s)) % len(s)
+	return append(CloneSlice(s[n:]), s[:n]...)
+}
+
+// BinarySearch searches for target in sorted slice s.
+// Returns the index of target and true when it's found,
+// otherwise the index where target would be inserted and false.
+func BinarySearch[T cmp.Ordered](s []T, target T) (int, bool) {
+	return slices.BinarySearch(s, target)
+}
+
+// MergeSorted merges sorted slices a and b into a new sorted slice.
+// When dedup is true, repeating items are dropped.
+func MergeSorted[T cmp.Ordered](a, b []T, dedup bool) []T {
+	merged := make([]T, 0, len(a)+len(b))
+	add := func(item T) {
+		if dedup && len(merged) > 0 && merged[len(merged)-1] == item {
+			return
+		}
+		merged = append(merged, item)
+	}
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		if b[j] < a[i] {
+			add(b[j])
+			j++
+		} else {
+			add(a[i])
+			i++
+		}
+	}
+	for _, each := range a[i:] {
+		add(each)
+	}
+	for _, each := range b[j:] {
+		add(each)
+	}
+	return merged
+}
+
+// Transpose swaps the rows and columns of matrix.
+// Matrix must be rectangular, it panics if the rows have different lengths.
+func Transpose[T any](matrix [][]T) [][]T {
+	if len(matrix) == 0 {
+		return [][]T{}
+	}
+	columns := len(matrix[0])
+	transposed := make([][]T, columns)
+	for i := range transposed {
+		transposed[i] = make([]T, len(matrix))
+	}
+	for y, row := range matrix {
+		if len(row) != columns {
+			panic(fmt.Sprintf("Ragged matrix: row %d has %d columns, expected %d.", y, len(row), columns))
+		}
+		for x, each := range row {
+			transposed[x][y] = each
+		}
+	}
+	return transposed
+}
+
 // ReadLines read all lines in file filep.
 // Empty lines are included.
 // Returned lines do not contain newlines at the end.
@@ -142,6 +868,34 @@ func ReadLines(filep string) (lines []string, err error) {
 	return
 }
 
+// ParseInts parses lines, e.g. from [gent.ReadLines], into ints.
+// Surrounding whitespace is ignored.
+// Error contains the 1-based line number of the first line that couldn't be parsed.
+func ParseInts(lines []string) ([]int, error) {
+	return parseLines(lines, strconv.Atoi)
+}
+
+// ParseFloats parses lines, e.g. from [gent.ReadLines], into float64s.
+// Surrounding whitespace is ignored.
+// Error contains the 1-based line number of the first line that couldn't be parsed.
+func ParseFloats(lines []string) ([]float64, error) {
+	return parseLines(lines, func(s string) (float64, error) {
+		return strconv.ParseFloat(s, 64)
+	})
+}
+
+func parseLines[T any](lines []string, parse func(string) (T, error)) ([]T, error) {
+	parsed := make([]T, len(lines))
+	for i, each := range lines {
+		value, err := parse(strings.TrimSpace(each))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		parsed[i] = value
+	}
+	return parsed, nil
+}
+
 // Tri returns one of the two values based on the condition.
 // I.e. this is a ternary "operator".
 func Tri[T any](condition bool, a, b T) T {
@@ -151,6 +905,14 @@ func Tri[T any](condition bool, a, b T) T {
 	return b
 }
 
+// ApplyIf returns f(v) when condition is true, otherwise v as is.
+func ApplyIf[T any](v T, condition bool, f func(T) T) T {
+	if condition {
+		return f(v)
+	}
+	return v
+}
+
 // Map a slice into another slice of the same size.
 func Map[T any, U any](s []T, f func(T) U) []U {
 	mapped := make([]U, len(s))
@@ -160,6 +922,53 @@ func Map[T any, U any](s []T, f func(T) U) []U {
 	return mapped
 }
 
+// Join formats the items in s with [fmt.Sprint] and joins them with sep.
+// E.g. [fmt.Stringer] items are formatted with their String method.
+func Join[T any](s []T, sep string) string {
+	return strings.Join(
+		Map(s, func(each T) string {
+			return fmt.Sprint(each)
+		}),
+		sep)
+}
+
+// Partition splits s into items for which pred returns true and the rest.
+// Order of the items is preserved in both.
+func Partition[T any](s []T, pred func(T) bool) (matched, rest []T) {
+	for _, each := range s {
+		if pred(each) {
+			matched = append(matched, each)
+		} else {
+			rest = append(rest, each)
+		}
+	}
+	return
+}
+
+// MapErr maps s with f like [gent.Map] but stops at the first error.
+// Returned error contains the index of the failed item and wraps the error from f.
+// Mapped slice is nil when an error is returned.
+func MapErr[T any, U any](s []T, f func(T) (U, error)) ([]U, error) {
+	mapped := make([]U, len(s))
+	for i, each := range s {
+		value, err := f(each)
+		if err != nil {
+			return nil, fmt.Errorf("index %d: %w", i, err)
+		}
+		mapped[i] = value
+	}
+	return mapped, nil
+}
+
+// FlatMap maps each item in s into a slice and concatenates the slices in order.
+func FlatMap[T any, U any](s []T, f func(T) []U) []U {
+	var mapped []U
+	for _, each := range s {
+		mapped = append(mapped, f(each)...)
+	}
+	return mapped
+}
+
 // Filter values in s with f.
 // When f returns true, item is included in the response slice.
 func Filter[T any](s []T, f func(T) bool) []T {
@@ -172,6 +981,192 @@ func Filter[T any](s []T, f func(T) bool) []T {
 	return filtered
 }
 
+// Reduce folds s from left to right into a single value starting from initial.
+// Initial is returned as is when s is empty.
+func Reduce[T any, U any](s []T, initial U, f func(acc U, item T) U) U {
+	acc := initial
+	for _, each := range s {
+		acc = f(acc, each)
+	}
+	return acc
+}
+
+// ReduceWhile folds s into a single value starting from initial.
+// Iteration stops when f returns false and the value returned along with it is the result.
+func ReduceWhile[T any, U any](s []T, initial U, f func(acc U, item T) (U, bool)) U {
+	acc := initial
+	for _, each := range s {
+		var next bool
+		if acc, next = f(acc, each); !next {
+			break
+		}
+	}
+	return acc
+}
+
+// Number is a constraint that permits any integer or floating-point type.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// SafeDiv returns a/b and true, or zero and false when b is zero.
+// Unlike integer division, it doesn't panic on zero divisor.
+func SafeDiv[T Number](a, b T) (T, bool) {
+	if b == 0 {
+		return 0, false
+	}
+	return a / b, true
+}
+
+// Iterate applies f n times, starting from initial, and returns the result.
+// Initial is returned as is when n is less than 1.
+func Iterate[T any](initial T, n int, f func(T) T) T {
+	value := initial
+	for i := 0; i < n; i++ {
+		value = f(value)
+	}
+	return value
+}
+
+// SumBy sums the values that f projects from the items in s.
+func SumBy[T any, N Number](s []T, f func(T) N) N {
+	var sum N
+	for _, each := range s {
+		sum += f(each)
+	}
+	return sum
+}
+
+// First returns the first item in s.
+// False is returned when s is empty.
+func First[T any](s []T) (T, bool) {
+	if len(s) == 0 {
+		var zero T
+		return zero, false
+	}
+	return s[0], true
+}
+
+// Last returns the last item in s.
+// False is returned when s is empty.
+func Last[T any](s []T) (T, bool) {
+	if len(s) == 0 {
+		var zero T
+		return zero, false
+	}
+	return s[len(s)-1], true
+}
+
+// Contains returns true when target exists in s.
+func Contains[T comparable](s []T, target T) bool {
+	return IndexOf(s, target) >= 0
+}
+
+// IndexOf returns the index of the first target in s, or -1 if it doesn't exist.
+func IndexOf[T comparable](s []T, target T) int {
+	for i, each := range s {
+		if each == target {
+			return i
+		}
+	}
+	return -1
+}
+
+// Find returns the first item in s for which pred returns true.
+// Zero value and false are returned when there's no such item.
+func Find[T any](s []T, pred func(T) bool) (T, bool) {
+	for _, each := range s {
+		if pred(each) {
+			return each, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// Any returns true when pred returns true for at least one item in s.
+// It's false for an empty slice.
+func Any[T any](s []T, pred func(T) bool) bool {
+	_, found := Find(s, pred)
+	return found
+}
+
+// All returns true when pred returns true for every item in s.
+// It's true for an empty slice.
+func All[T any](s []T, pred func(T) bool) bool {
+	return !Any(s, func(each T) bool { return !pred(each) })
+}
+
+// None returns true when pred returns false for every item in s.
+// It's true for an empty slice.
+func None[T any](s []T, pred func(T) bool) bool {
+	return !Any(s, pred)
+}
+
+// At returns the item at index i in s.
+// False is returned when i is out of range, including negative indexes.
+func At[T any](s []T, i int) (T, bool) {
+	if i < 0 || i >= len(s) {
+		var zero T
+		return zero, false
+	}
+	return s[i], true
+}
+
+// IsZero returns true when v is the zero value of T.
+func IsZero[T comparable](v T) bool {
+	var zero T
+	return v == zero
+}
+
+// NonZero returns true when v is not the zero value of T.
+// Inverse of [gent.IsZero], suitable as a predicate for [gent.Filter].
+func NonZero[T comparable](v T) bool {
+	return !IsZero(v)
+}
+
+// CloneSlice returns a shallow copy of s.
+// The returned slice has its own backing array but the elements are the same.
+func CloneSlice[T any](s []T) []T {
+	if s == nil {
+		return nil
+	}
+	cloned := make([]T, len(s))
+	copy(cloned, s)
+	return cloned
+}
+
+// ThrottledMap maps s with f like [gent.Map] but calls f concurrently.
+// At most maxConcurrent calls run at the same time, values below 1 are treated as 1.
+// Order of the mapped items matches s.
+// When any call fails, the error of the first failing item is returned and the slice is nil.
+func ThrottledMap[T, U any](s []T, maxConcurrent int, f func(T) (U, error)) ([]U, error) {
+	mapped := make([]U, len(s))
+	errs := make([]error, len(s))
+	semaphore := make(chan struct{}, max(maxConcurrent, 1))
+	var wg sync.WaitGroup
+	for i, each := range s {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(i int, item T) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+			mapped[i], errs[i] = f(item)
+		}(i, each)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return mapped, nil
+}
+
 // OrPanic2 returns function that returns value if err is nil, else panics with message.
 // Useful for cases where failure should result in panic
 // and you don't want to deal with the returned error.
@@ -186,6 +1181,39 @@ func OrPanic2[T any](value T, err error) func(message string) T {
 	}
 }
 
+// Must returns value or panics with err when it's not nil.
+// Use [gent.OrPanic2] instead when you have a meaningful message.
+func Must[T any](value T, err error) T {
+	if err != nil {
+		panic(err)
+	}
+	return value
+}
+
+// OrElse calls primary and when it fails, calls fallback.
+// Error of fallback is returned when both fail.
+func OrElse[T any](primary func() (T, error), fallback func() (T, error)) (T, error) {
+	if value, err := primary(); err == nil {
+		return value, nil
+	}
+	return fallback()
+}
+
+// MustOk returns value if ok is true, else panics.
+// Useful for lookups such as map access that are known to succeed.
+func MustOk[T any](value T, ok bool) T {
+	if !ok {
+		panic("Not ok.")
+	}
+	return value
+}
+
+// CombineErrors joins the non-nil errors into one, nil is returned when there are none.
+// Combined error matches each of the errors with [errors.Is], see [errors.Join].
+func CombineErrors(errs ...error) error {
+	return errors.Join(errs...)
+}
+
 // NewOption is a general function to implement option pattern.
 func NewOption[T any](t T, options ...func(t *T)) T {
 	for _, each := range options {
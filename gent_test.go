@@ -1,15 +1,54 @@
 package gent
 
 import (
+	"cmp"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 	"sort"
 	"strconv"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
 
+func TestPairSwap(t *testing.T) {
+	req := require.New(t)
+	pair := NewPair(1, "one")
+	swapped := pair.Swap()
+	req.Equal(NewPair("one", 1), swapped)
+	req.Equal(pair, swapped.Swap())
+}
+
+func TestPairToSlice(t *testing.T) {
+	require.Equal(t, []int{1, 2}, PairToSlice(NewPair(1, 2)))
+}
+
+func TestPairsFromChannels(t *testing.T) {
+	req := require.New(t)
+	numbers := make(chan int, 5)
+	letters := make(chan string)
+	go func() {
+		defer close(numbers)
+		for i := 1; i <= 5; i++ {
+			numbers <- i
+		}
+	}()
+	go func() {
+		defer close(letters)
+		for _, each := range []string{"a", "b", "c"} {
+			letters <- each
+		}
+	}()
+	req.Equal(
+		[]Pair[int, string]{NewPair(1, "a"), NewPair(2, "b"), NewPair(3, "c")},
+		PairsFromChannels(numbers, letters))
+}
+
 func TestSet(t *testing.T) {
 	t.Run("teddy", func(t *testing.T) {
 		req := require.New(t)
@@ -47,6 +86,55 @@ func TestSet(t *testing.T) {
 		req.Equal(0, set.Len(), "only max isn't zero")
 	})
 
+	t.Run("AddIf", func(t *testing.T) {
+		req := require.New(t)
+		set := NewSet[int]()
+		req.False(set.AddIf(1, false))
+		req.False(set.Has(1))
+		req.Equal(0, set.Len())
+		req.True(set.AddIf(1, true))
+		req.False(set.AddIf(1, true), "already exists")
+		req.False(set.AddIf(1, false))
+		req.Equal([]int{1}, set.ToSlice())
+	})
+
+	t.Run("AddAll and RemoveAll", func(t *testing.T) {
+		req := require.New(t)
+		set := NewSet("a")
+		req.Equal(0, set.AddAll(), "no items")
+		req.Equal(2, set.AddAll("a", "b", "c", "b"))
+		req.Equal(1, set.AddSlice([]string{"c", "d", "d"}))
+		req.Equal(0, set.AddSlice(nil))
+		req.True(NewSet("a", "b", "c", "d").Equal(set))
+
+		req.Equal(0, set.RemoveAll(), "no items")
+		req.Equal(2, set.RemoveAll("a", "x", "b", "a"))
+		req.True(NewSet("c", "d").Equal(set))
+	})
+
+	t.Run("Pop", func(t *testing.T) {
+		req := require.New(t)
+		set := NewSet(1, 2, 3, 4)
+		popped := NewSet[int]()
+		for {
+			item, ok := set.Pop()
+			if !ok {
+				break
+			}
+			req.True(popped.Add(item), "popped once")
+		}
+		req.Equal(0, set.Len())
+		req.True(NewSet(1, 2, 3, 4).Equal(popped))
+	})
+
+	t.Run("Clear", func(t *testing.T) {
+		req := require.New(t)
+		set := NewSet(1, 2, 3)
+		req.Equal(3, set.Clear())
+		req.Equal(0, set.Len())
+		req.Equal(0, set.Clear(), "nothing left to clear")
+	})
+
 	t.Run("Equal", func(t *testing.T) {
 		req := require.New(t)
 
@@ -57,6 +145,91 @@ func TestSet(t *testing.T) {
 		req.False(set.Equal(NewSet(append([]string{"1a"}, items[1:]...)...)), "swapped first item")
 	})
 
+	t.Run("EqualDiff", func(t *testing.T) {
+		req := require.New(t)
+		equal, missing, extra := NewSet(1, 2, 3).EqualDiff(NewSet(2, 3, 4, 5))
+		sort.Ints(extra)
+		req.False(equal)
+		req.Equal([]int{1}, missing)
+		req.Equal([]int{4, 5}, extra)
+
+		equal, missing, extra = NewSet(1, 2).EqualDiff(NewSet(2, 1))
+		req.True(equal)
+		req.Empty(missing)
+		req.Empty(extra)
+	})
+
+	t.Run("IsSubset and IsSuperset", func(t *testing.T) {
+		type param struct {
+			name     string
+			a        []int
+			b        []int
+			subset   bool
+			superset bool
+		}
+		run := func(p param) {
+			t.Run(p.name, func(t *testing.T) {
+				req := require.New(t)
+				a, b := NewSet(p.a...), NewSet(p.b...)
+				req.Equal(p.subset, a.IsSubset(b), "subset")
+				req.Equal(p.superset, a.IsSuperset(b), "superset")
+			})
+		}
+		run(param{name: "equal", a: []int{1, 2}, b: []int{2, 1}, subset: true, superset: true})
+		run(param{name: "strict subset", a: []int{1}, b: []int{1, 2}, subset: true})
+		run(param{name: "strict superset", a: []int{1, 2}, b: []int{2}, superset: true})
+		run(param{name: "disjoint", a: []int{1, 2}, b: []int{3, 4}})
+		run(param{name: "overlapping", a: []int{1, 2}, b: []int{2, 3}})
+		run(param{name: "empty", subset: true, superset: true})
+		run(param{name: "empty receiver", b: []int{1}, subset: true})
+		run(param{name: "empty other", a: []int{1}, superset: true})
+	})
+
+	t.Run("AssertEqual", func(t *testing.T) {
+		req := require.New(t)
+		mock := &mockT{}
+		NewSet(1, 2).AssertEqual(mock, NewSet(2, 1), "same")
+		req.False(mock.failed)
+		req.Empty(mock.errors)
+
+		NewSet(1, 2).AssertEqual(mock, NewSet(2, 3), "different")
+		req.True(mock.failed)
+		req.Len(mock.errors, 1)
+		req.Contains(mock.errors[0], "Sets differ, missing: [1], extra: [3].")
+		req.Contains(mock.errors[0], "different")
+	})
+
+	t.Run("Equal nil", func(t *testing.T) {
+		req := require.New(t)
+		var nilSet *Set[int]
+		req.True(NewSet[int]().Equal(nil), "empty equals nil")
+		req.False(NewSet(1).Equal(nil), "non-empty doesn't equal nil")
+		req.True(nilSet.Equal(nil), "nil equals nil")
+		req.True(nilSet.Equal(NewSet[int]()), "nil equals empty")
+		req.False(nilSet.Equal(NewSet(1)), "nil doesn't equal non-empty")
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		req := require.New(t)
+		var set *Set[string]
+		req.Equal(0, set.Len())
+		req.Equal(0, set.Count())
+		req.False(set.Has("a"))
+		req.False(set.Contains("a"))
+		req.False(set.Remove("a"))
+		req.Equal([]string{}, set.ToSlice())
+		set.ForEach(func(_ string, _ func()) { req.Fail("ForEach on nil") })
+		set.ForEachAll(func(_ string) { req.Fail("ForEachAll on nil") })
+	})
+
+	t.Run("zero value", func(t *testing.T) {
+		req := require.New(t)
+		var set Set[string]
+		req.False(set.Has("a"))
+		req.True(set.Add("a"))
+		req.True(set.Has("a"))
+	})
+
 	t.Run("ForEach stop", func(t *testing.T) {
 		set := NewSet(3, 1, 3)
 		counter := 0
@@ -92,6 +265,66 @@ func TestSet(t *testing.T) {
 		require.Empty(t, items, "ForEachAll should've removed all items")
 	})
 
+	t.Run("All", func(t *testing.T) {
+		req := require.New(t)
+		set := NewSet("a", "b", "c")
+		collected := NewSet[string]()
+		for each := range set.All() {
+			collected.Add(each)
+		}
+		req.True(set.Equal(collected))
+
+		counter := 0
+		for range set.All() {
+			counter++
+			if counter == 2 {
+				break
+			}
+		}
+		req.Equal(2, counter, "stopped at break")
+
+		var nilSet *Set[string]
+		for range nilSet.All() {
+			req.Fail("All on nil")
+		}
+	})
+
+	t.Run("ForEachIndexed", func(t *testing.T) {
+		req := require.New(t)
+		items := []string{"a", "b", "c", "d"}
+		visited := NewSet[string]()
+		indexes := []int{}
+		NewSet(items...).ForEachIndexed(func(i int, each string) {
+			req.True(visited.Add(each), "visited once")
+			indexes = append(indexes, i)
+		})
+		req.Equal([]int{0, 1, 2, 3}, indexes)
+		req.True(NewSet(items...).Equal(visited))
+	})
+
+	t.Run("Clone", func(t *testing.T) {
+		req := require.New(t)
+		original := NewSet("a", "b")
+		cloned := original.Clone()
+		req.True(original.Equal(cloned))
+		cloned.Add("c")
+		original.Remove("a")
+		req.True(NewSet("b").Equal(original))
+		req.True(NewSet("a", "b", "c").Equal(cloned))
+
+		empty := NewSet[string]().Clone()
+		req.NotNil(empty.m)
+		req.True(empty.Add("a"))
+	})
+
+	t.Run("With and Without", func(t *testing.T) {
+		req := require.New(t)
+		set := NewSet("a")
+		chained := set.With("b", "c").Without("a").With("d").Without("x")
+		req.Same(set, chained, "chaining returns the receiver")
+		req.True(NewSet("b", "c", "d").Equal(set))
+	})
+
 	t.Run("ToSlice", func(t *testing.T) {
 		set := NewSet("m1", "o2", "o2", "n3")
 		sliced := set.ToSlice()
@@ -100,12 +333,624 @@ func TestSet(t *testing.T) {
 	})
 }
 
+func TestNewSetFromSlice(t *testing.T) {
+	req := require.New(t)
+	req.True(NewSet[int]().Equal(NewSetFromSlice[int](nil)))
+	req.NotNil(NewSetFromSlice[int](nil).m)
+	req.True(NewSet[int]().Equal(NewSetFromSlice([]int{})))
+	req.True(NewSet(1, 2, 3).Equal(NewSetFromSlice([]int{3, 1, 2, 1, 3})))
+}
+
+func TestNewSetFromKeys(t *testing.T) {
+	req := require.New(t)
+	req.True(NewSet[string]().Equal(NewSetFromKeys[string, int](nil)))
+	req.NotNil(NewSetFromKeys[string, int](nil).m)
+	req.True(NewSet[string]().Equal(NewSetFromKeys(map[string]int{})))
+	req.True(NewSet("a", "b").Equal(NewSetFromKeys(map[string]int{"a": 1, "b": 1})))
+}
+
+func TestNewSetFromSeq(t *testing.T) {
+	req := require.New(t)
+	lines := strings.Split("info a\ndebug b\ninfo a\ninfo c\n", "\n")
+	infoLines := func(yield func(string) bool) {
+		for _, each := range lines {
+			if strings.HasPrefix(each, "info") && !yield(each) {
+				return
+			}
+		}
+	}
+	req.True(NewSet("info a", "info c").Equal(NewSetFromSeq(infoLines)))
+	req.True(NewSet("x").Equal(NewSetFromSeq(NewSet("x").All())))
+	req.Equal(0, NewSetFromSeq(slices.Values([]int{})).Len())
+}
+
+func TestSetOperations(t *testing.T) {
+	type param struct {
+		name         string
+		a            []int
+		b            []int
+		union        []int
+		intersection []int
+		difference   []int
+	}
+	run := func(p param) {
+		t.Run(p.name, func(t *testing.T) {
+			req := require.New(t)
+			a, b := NewSet(p.a...), NewSet(p.b...)
+			assertSet := func(expected []int, actual *Set[int], name string) {
+				req.NotNil(actual.m, name)
+				req.True(NewSet(expected...).Equal(actual), "%s: %v", name, actual.ToSlice())
+			}
+			assertSet(p.union, a.Union(b), "union")
+			assertSet(p.intersection, a.Intersection(b), "intersection")
+			assertSet(p.difference, a.Difference(b), "difference")
+			assertSet(p.a, a, "a untouched")
+			assertSet(p.b, b, "b untouched")
+		})
+	}
+
+	run(param{
+		name:         "overlapping",
+		a:            []int{1, 2, 3},
+		b:            []int{2, 3, 4},
+		union:        []int{1, 2, 3, 4},
+		intersection: []int{2, 3},
+		difference:   []int{1},
+	})
+	run(param{
+		name:         "disjoint",
+		a:            []int{1, 2},
+		b:            []int{3, 4},
+		union:        []int{1, 2, 3, 4},
+		intersection: nil,
+		difference:   []int{1, 2},
+	})
+	run(param{
+		name:         "identical",
+		a:            []int{1, 2},
+		b:            []int{2, 1},
+		union:        []int{1, 2},
+		intersection: []int{1, 2},
+		difference:   nil,
+	})
+	run(param{
+		name:         "empty",
+		a:            nil,
+		b:            nil,
+		union:        nil,
+		intersection: nil,
+		difference:   nil,
+	})
+	run(param{
+		name:         "empty receiver",
+		a:            nil,
+		b:            []int{1},
+		union:        []int{1},
+		intersection: nil,
+		difference:   nil,
+	})
+}
+
+func TestSetSymmetricDifference(t *testing.T) {
+	type param struct {
+		name     string
+		a        []string
+		b        []string
+		expected []string
+	}
+	run := func(p param) {
+		t.Run(p.name, func(t *testing.T) {
+			req := require.New(t)
+			a, b := NewSet(p.a...), NewSet(p.b...)
+			req.True(NewSet(p.expected...).Equal(a.SymmetricDifference(b)))
+			req.True(NewSet(p.a...).Equal(a), "a untouched")
+			req.True(NewSet(p.b...).Equal(b), "b untouched")
+		})
+	}
+	run(param{
+		name:     "overlapping",
+		a:        []string{"a", "b"},
+		b:        []string{"b", "c"},
+		expected: []string{"a", "c"},
+	})
+	run(param{name: "identical", a: []string{"a", "b"}, b: []string{"b", "a"}, expected: nil})
+	run(param{
+		name:     "disjoint",
+		a:        []string{"a", "b"},
+		b:        []string{"c", "d"},
+		expected: []string{"a", "b", "c", "d"},
+	})
+}
+
+func TestSetIntersectSlice(t *testing.T) {
+	req := require.New(t)
+	set := NewSet(1, 2, 3)
+	req.True(NewSet(2, 3).Equal(set.IntersectSlice([]int{3, 4, 2, 3})))
+	req.True(NewSet(1, 2, 3).Equal(set), "receiver untouched")
+	req.Equal(0, set.IntersectSlice(nil).Len())
+}
+
+func TestSetOperationsSorted(t *testing.T) {
+	req := require.New(t)
+	a := NewSet(5, 1, 3, 7)
+	b := NewSet(3, 4, 5, 6)
+	descending := func(x, y int) int { return y - x }
+	req.Equal([]int{1, 3, 4, 5, 6, 7}, a.UnionSorted(b, cmp.Compare[int]))
+	req.Equal([]int{7, 6, 5, 4, 3, 1}, a.UnionSorted(b, descending))
+	req.Equal([]int{3, 5}, a.IntersectionSorted(b, cmp.Compare[int]))
+	req.Equal([]int{1, 7}, a.DifferenceSorted(b, cmp.Compare[int]))
+	req.Equal([]int{4, 6}, b.DifferenceSorted(a, cmp.Compare[int]))
+	req.Equal([]int{}, NewSet[int]().UnionSorted(NewSet[int](), cmp.Compare[int]))
+}
+
+func TestSetDiff(t *testing.T) {
+	req := require.New(t)
+	actual := NewSet("kept", "removed")
+	desired := NewSet("kept", "added")
+	added, removed := actual.Diff(desired)
+	req.True(NewSet("added").Equal(added))
+	req.True(NewSet("removed").Equal(removed))
+
+	added, removed = actual.Diff(actual)
+	req.Equal(0, added.Len())
+	req.Equal(0, removed.Len())
+}
+
+func TestSetPartitionInto(t *testing.T) {
+	req := require.New(t)
+	set := NewSet(1, 2, 3, 4, 5, 6, 7)
+	partitions := set.PartitionInto(3)
+	sizes := Map(partitions, (*Set[int]).Len)
+	sort.Ints(sizes)
+	req.Equal([]int{2, 2, 3}, sizes)
+	union := NewSet[int]()
+	for _, each := range partitions {
+		req.Equal(each.Len(), union.AddSlice(each.ToSlice()), "no overlap")
+	}
+	req.True(set.Equal(union))
+
+	req.Len(NewSet[int]().PartitionInto(2), 2)
+	req.PanicsWithValue("Invalid partition count: 0.", func() { set.PartitionInto(0) })
+}
+
+func TestSetSubsets(t *testing.T) {
+	req := require.New(t)
+	subsets := NewSet("a", "b", "c").Subsets()
+	req.Len(subsets, 8)
+	keys := NewSet[string]()
+	for _, each := range subsets {
+		sliced := each.ToSlice()
+		sort.Strings(sliced)
+		keys.Add(strings.Join(sliced, ""))
+	}
+	req.True(NewSet("", "a", "b", "c", "ab", "ac", "bc", "abc").Equal(keys))
+
+	req.Len(NewSet[int]().Subsets(), 1, "only the empty set")
+
+	large := NewSet[int]()
+	for i := 0; i <= MaxSubsetsLen; i++ {
+		large.Add(i)
+	}
+	req.Panics(func() { large.Subsets() })
+}
+
+func TestSetFilter(t *testing.T) {
+	req := require.New(t)
+	set := NewSet(1, 2, 3, 4)
+	req.True(NewSet(2, 4).Equal(set.Filter(func(i int) bool { return i%2 == 0 })))
+	req.True(NewSet(1, 2, 3, 4).Equal(set), "source untouched")
+	req.Equal(0, set.Filter(func(_ int) bool { return false }).Len())
+}
+
+func TestMapSet(t *testing.T) {
+	req := require.New(t)
+	set := NewSet(-2, -1, 1, 2, 3)
+	abs := func(i int) int { return Tri(i < 0, -i, i) }
+	mapped := MapSet(set, abs)
+	req.True(NewSet(1, 2, 3).Equal(mapped), "duplicates collapsed")
+	req.Less(mapped.Len(), set.Len())
+	req.True(NewSet(-2, -1, 1, 2, 3).Equal(set), "source untouched")
+}
+
+type mockT struct {
+	errors []string
+	failed bool
+}
+
+func (v *mockT) Errorf(format string, args ...any) {
+	v.errors = append(v.errors, fmt.Sprintf(format, args...))
+}
+
+func (v *mockT) FailNow() {
+	v.failed = true
+}
+
+func TestMapSetMerge(t *testing.T) {
+	req := require.New(t)
+	set := NewSet("apple", "avocado", "banana")
+	firstLetter := func(s string) byte { return s[0] }
+	req.True(NewSet[byte]('a', 'b').Equal(MapSetMerge(set, firstLetter)))
+	req.Equal(map[byte]int{'a': 2, 'b': 1}, MapSetCount(set, firstLetter))
+	req.Empty(MapSetCount(NewSet[string](), firstLetter))
+}
+
+func TestSetString(t *testing.T) {
+	req := require.New(t)
+	req.Equal("Set{}", NewSet[int]().String())
+	req.Equal("Set{1}", NewSet(1).String())
+
+	s := fmt.Sprint(NewSet("alpha", "beta", "gamma"))
+	req.Len(s, len("Set{alpha, beta, gamma}"))
+	req.True(strings.HasPrefix(s, "Set{"))
+	req.True(strings.HasSuffix(s, "}"))
+	for _, each := range []string{"alpha", "beta", "gamma"} {
+		req.Contains(s, each)
+	}
+}
+
+func TestSetJSON(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		req := require.New(t)
+		original := NewSet("a", "b", "c")
+		b, err := json.Marshal(original)
+		req.Nil(err)
+		var items []string
+		req.Nil(json.Unmarshal(b, &items))
+		req.ElementsMatch([]string{"a", "b", "c"}, items)
+
+		unmarshalled := NewSet[string]()
+		req.Nil(json.Unmarshal(b, unmarshalled))
+		req.True(original.Equal(unmarshalled))
+	})
+
+	t.Run("duplicates", func(t *testing.T) {
+		req := require.New(t)
+		set := NewSet(9)
+		req.Nil(json.Unmarshal([]byte("[1, 2, 1, 3]"), set))
+		req.True(NewSet(1, 2, 3).Equal(set), "replaced and collapsed")
+	})
+
+	t.Run("field", func(t *testing.T) {
+		req := require.New(t)
+		type config struct {
+			Tags *Set[string] `json:"tags"`
+		}
+		var c config
+		req.Nil(json.Unmarshal([]byte(`{"tags": ["x", "y"]}`), &c))
+		req.True(NewSet("x", "y").Equal(c.Tags))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		require.Error(t, json.Unmarshal([]byte(`{"a": 1}`), NewSet[int]()))
+	})
+}
+
+func TestToMap(t *testing.T) {
+	req := require.New(t)
+	req.Equal(
+		map[string]int{"a": 1, "bb": 2, "ccc": 3},
+		ToMap(NewSet("a", "bb", "ccc"), func(s string) int { return len(s) }))
+	req.Empty(ToMap(NewSet[string](), func(s string) int { return len(s) }))
+}
+
+func TestMarshalJSONSorted(t *testing.T) {
+	req := require.New(t)
+	set := NewSet("kiwi", "apple", "mango", "banana")
+	first, err := MarshalJSONSorted(set)
+	req.Nil(err)
+	req.Equal(`["apple","banana","kiwi","mango"]`, string(first))
+	second, err := MarshalJSONSorted(set)
+	req.Nil(err)
+	req.Equal(first, second)
+
+	empty, err := MarshalJSONSorted(NewSet[int]())
+	req.Nil(err)
+	req.Equal("[]", string(empty))
+}
+
+func TestSliceContainsAll(t *testing.T) {
+	req := require.New(t)
+	haystack := []int{1, 2, 3, 4}
+	req.True(SliceContainsAll(haystack, []int{4, 2, 2}), "overlapping")
+	req.False(SliceContainsAll(haystack, []int{4, 5}), "partially overlapping")
+	req.False(SliceContainsAll(haystack, []int{5, 6}), "disjoint")
+	req.True(SliceContainsAll(haystack, nil), "no needles")
+}
+
+func TestSlicesIntersect(t *testing.T) {
+	req := require.New(t)
+	req.True(SlicesIntersect([]string{"a", "b"}, []string{"c", "b"}), "overlapping")
+	req.False(SlicesIntersect([]string{"a", "b"}, []string{"c", "d"}), "disjoint")
+	req.False(SlicesIntersect(nil, []string{"a"}), "empty")
+}
+
+func TestSliceDifference(t *testing.T) {
+	req := require.New(t)
+	req.Equal(
+		[]string{"c", "a", "c", "a"},
+		SliceDifference([]string{"c", "b", "a", "c", "d", "a"}, []string{"d", "b", "x"}))
+	req.Nil(SliceDifference([]int{1, 2}, []int{2, 1}))
+	req.Equal([]int{2, 1}, SliceDifference([]int{2, 1}, nil))
+}
+
+func TestDistinct(t *testing.T) {
+	req := require.New(t)
+	req.Equal([]int{1, 2, 3}, Distinct([]int{1, 2, 1, 3, 2}))
+	req.Equal([]string{"b", "a", "c"}, Distinct([]string{"b", "a", "b", "c", "a"}))
+	req.Nil(Distinct[int](nil))
+}
+
+func TestDedupConsecutive(t *testing.T) {
+	req := require.New(t)
+	req.Equal([]string{"a", "b", "a"}, DedupConsecutive([]string{"a", "a", "b", "a"}))
+	req.Equal([]int{1, 2, 3, 1}, DedupConsecutive([]int{1, 1, 1, 2, 3, 3, 1}))
+	req.Equal([]int{}, DedupConsecutive([]int{}))
+	req.Nil(DedupConsecutive[int](nil))
+}
+
+func TestGroupBy(t *testing.T) {
+	req := require.New(t)
+	parity := func(i int) string { return Tri(i%2 == 0, "even", "odd") }
+	req.Equal(
+		map[string][]int{"even": {4, 2, 8}, "odd": {1, 3, 7}},
+		GroupBy([]int{1, 4, 3, 2, 7, 8}, parity))
+	req.Equal(
+		map[byte][]string{'a': {"ant", "apple"}, 'b': {"bee", "bat", "bear"}},
+		GroupBy(
+			[]string{"bee", "ant", "bat", "apple", "bear"},
+			func(s string) byte { return s[0] }))
+	groups := GroupBy(nil, parity)
+	req.NotNil(groups)
+	req.Empty(groups)
+}
+
+func TestCountBy(t *testing.T) {
+	req := require.New(t)
+	firstLetter := func(s string) string { return s[:1] }
+	words := []string{"cat", "apple", "cow", "bee", "ant", "crab"}
+	counts := CountBy(words, firstLetter)
+	req.Equal(map[string]int{"a": 2, "b": 1, "c": 3}, counts)
+	groups := GroupBy(words, firstLetter)
+	req.Len(counts, len(groups))
+	for key, group := range groups {
+		req.Equal(len(group), counts[key], "group %s", key)
+	}
+	req.Empty(CountBy(nil, firstLetter))
+}
+
+func TestKeysValuesEntries(t *testing.T) {
+	req := require.New(t)
+	m := map[string]int{"b": 2, "c": 3, "a": 1}
+
+	keys := Keys(m)
+	sort.Strings(keys)
+	req.Equal([]string{"a", "b", "c"}, keys)
+
+	values := Values(m)
+	sort.Ints(values)
+	req.Equal([]int{1, 2, 3}, values)
+
+	entries := Entries(m)
+	sort.Slice(entries, func(i, j int) bool { return entries[i].First < entries[j].First })
+	req.Equal([]Pair[string, int]{NewPair("a", 1), NewPair("b", 2), NewPair("c", 3)}, entries)
+
+	var nilMap map[string]int
+	req.Equal([]string{}, Keys(nilMap))
+	req.Equal([]int{}, Values(nilMap))
+	req.Equal([]Pair[string, int]{}, Entries(nilMap))
+}
+
+func TestKeysWithValue(t *testing.T) {
+	req := require.New(t)
+	config := map[string]string{
+		"input":  "utf-8",
+		"output": "utf-8",
+		"log":    "ascii",
+	}
+	keys := KeysWithValue(config, "utf-8")
+	sort.Strings(keys)
+	req.Equal([]string{"input", "output"}, keys)
+	req.Empty(KeysWithValue(config, "latin-1"))
+}
+
+func TestMergeMaps(t *testing.T) {
+	sum := func(_ string, av, bv int) int { return av + bv }
+	t.Run("disjoint", func(t *testing.T) {
+		require.Equal(
+			t,
+			map[string]int{"a": 1, "b": 2},
+			MergeMaps(map[string]int{"a": 1}, map[string]int{"b": 2}, sum))
+	})
+	t.Run("overlapping", func(t *testing.T) {
+		req := require.New(t)
+		a := map[string]int{"a": 1, "b": 2}
+		b := map[string]int{"a": 10, "b": 20}
+		req.Equal(map[string]int{"a": 11, "b": 22}, MergeMaps(a, b, sum))
+		req.Equal(
+			map[string]int{"a": 10, "b": 2},
+			MergeMaps(a, b, func(key string, av, bv int) int { return Tri(key == "a", bv, av) }))
+		req.Equal(map[string]int{"a": 1, "b": 2}, a, "a untouched")
+		req.Equal(map[string]int{"a": 10, "b": 20}, b, "b untouched")
+	})
+	t.Run("nil", func(t *testing.T) {
+		require.Empty(t, MergeMaps[string, int](nil, nil, sum))
+	})
+}
+
+func TestFilterMapEntries(t *testing.T) {
+	req := require.New(t)
+	config := map[string]string{"host": "localhost", "port": "", "user": "admin"}
+	filtered := FilterMapEntries(config, func(key, value string) bool {
+		return value != "" && key != "user"
+	})
+	req.Equal(map[string]string{"host": "localhost"}, filtered)
+	req.Len(config, 3, "source untouched")
+}
+
+func TestGetOrInsert(t *testing.T) {
+	req := require.New(t)
+	calls := 0
+	create := func() []string {
+		calls++
+		return []string{}
+	}
+	groups := map[string][]string{"b": {"bee"}}
+	req.Equal([]string{"bee"}, GetOrInsert(groups, "b", create))
+	req.Equal(0, calls, "existing key")
+	req.Equal([]string{}, GetOrInsert(groups, "a", create))
+	req.Equal(1, calls, "absent key")
+	req.Contains(groups, "a")
+	GetOrInsert(groups, "a", create)
+	req.Equal(1, calls, "inserted key")
+}
+
+func TestMovingAverage(t *testing.T) {
+	req := require.New(t)
+	series := []float64{1, 2, 3, 4, 5, 9}
+	req.InDeltaSlice([]float64{2, 3, 4, 6}, MovingAverage(series, 3), 1e-9)
+	req.Equal(series, MovingAverage(series, 1))
+	req.Equal([]float64{4}, MovingAverage(series, len(series)))
+	req.Empty(MovingAverage(series, len(series)+1))
+	req.Empty(MovingAverage(series, 0))
+}
+
+func TestEnumerate(t *testing.T) {
+	req := require.New(t)
+	req.Equal(
+		[]Pair[int, string]{NewPair(0, "a"), NewPair(1, "b"), NewPair(2, "c")},
+		Enumerate([]string{"a", "b", "c"}))
+	req.Empty(Enumerate[string](nil))
+	oddIndexes := Filter(
+		Enumerate([]string{"a", "b", "c", "d"}),
+		func(p Pair[int, string]) bool { return p.First%2 != 0 })
+	req.Equal([]Pair[int, string]{NewPair(1, "b"), NewPair(3, "d")}, oddIndexes)
+}
+
+func TestChunk(t *testing.T) {
+	req := require.New(t)
+	req.Equal([][]int{{1, 2}, {3, 4}}, Chunk([]int{1, 2, 3, 4}, 2), "exact")
+	req.Equal([][]int{{1, 2, 3}, {4, 5}}, Chunk([]int{1, 2, 3, 4, 5}, 3), "remainder")
+	req.Equal([][]int{{1, 2}}, Chunk([]int{1, 2}, 5), "size larger than slice")
+	req.Equal([][]int{}, Chunk([]int{}, 2), "empty")
+	req.PanicsWithValue("Invalid chunk size: 0.", func() { Chunk([]int{1}, 0) })
+	req.PanicsWithValue("Invalid chunk size: -1.", func() { Chunk([]int{1}, -1) })
+}
+
+func TestResize(t *testing.T) {
+	req := require.New(t)
+	row := []string{"a", "b", "c"}
+	req.Equal([]string{"a", "b"}, Resize(row, 2, "-"))
+	req.Equal([]string{"a", "b", "c", "-", "-"}, Resize(row, 5, "-"))
+	req.Equal([]string{"a", "b", "c"}, Resize(row, 3, "-"))
+	req.Equal([]string{"a", "b", "c"}, row, "source untouched")
+	req.Equal([]string{}, Resize(row, 0, "-"))
+	req.Equal([]int{7, 7}, Resize(nil, 2, 7))
+}
+
+func TestReverse(t *testing.T) {
+	req := require.New(t)
+	s := []int{1, 2, 3, 4}
+	req.Equal([]int{4, 3, 2, 1}, Reverse(s))
+	req.Equal([]int{1, 2, 3, 4}, s, "source untouched")
+	req.Equal([]int{1}, Reverse([]int{1}))
+	req.Nil(Reverse[int](nil))
+}
+
+func TestReverseInPlace(t *testing.T) {
+	req := require.New(t)
+	s := []int{1, 2, 3, 4, 5}
+	ReverseInPlace(s)
+	req.Equal([]int{5, 4, 3, 2, 1}, s)
+	single := []int{1}
+	ReverseInPlace(single)
+	req.Equal([]int{1}, single)
+	ReverseInPlace[int](nil)
+}
+
+func TestRotate(t *testing.T) {
+	req := require.New(t)
+	s := []int{1, 2, 3, 4, 5}
+	req.Equal([]int{3, 4, 5, 1, 2}, Rotate(s, 2))
+	req.Equal([]int{4, 5, 1, 2, 3}, Rotate(s, -2))
+	req.Equal([]int{2, 3, 4, 5, 1}, Rotate(s, 11))
+	req.Equal([]int{5, 1, 2, 3, 4}, Rotate(s, -6))
+	req.Equal(s, Rotate(s, 0))
+	req.Equal([]int{1, 2, 3, 4, 5}, s, "source untouched")
+	req.Nil(Rotate[int](nil, 3))
+}
+
+func TestBinarySearch(t *testing.T) {
+	req := require.New(t)
+	s := []string{"b", "d", "f"}
+	assertSearch := func(target string, expectedIndex int, expectedFound bool) {
+		i, found := BinarySearch(s, target)
+		req.Equal(expectedIndex, i, target)
+		req.Equal(expectedFound, found, target)
+	}
+	assertSearch("b", 0, true)
+	assertSearch("f", 2, true)
+	assertSearch("a", 0, false)
+	assertSearch("c", 1, false)
+	assertSearch("g", 3, false)
+}
+
+func TestMergeSorted(t *testing.T) {
+	req := require.New(t)
+	a := []int{1, 3, 3, 5, 9}
+	b := []int{2, 3, 4, 9, 10, 11}
+	merged := MergeSorted(a, b, false)
+	req.Equal([]int{1, 2, 3, 3, 3, 4, 5, 9, 9, 10, 11}, merged)
+	req.True(slices.IsSorted(merged))
+	req.Equal([]int{1, 2, 3, 4, 5, 9, 10, 11}, MergeSorted(a, b, true))
+	req.Equal([]string{"a", "b"}, MergeSorted(nil, []string{"a", "b"}, true))
+	req.Empty(MergeSorted[int](nil, nil, false))
+}
+
+func TestTranspose(t *testing.T) {
+	req := require.New(t)
+	req.Equal(
+		[][]int{{1, 4}, {2, 5}, {3, 6}},
+		Transpose([][]int{{1, 2, 3}, {4, 5, 6}}))
+	req.Equal([][]int{}, Transpose[int](nil))
+	req.PanicsWithValue(
+		"Ragged matrix: row 1 has 1 columns, expected 2.",
+		func() { Transpose([][]int{{1, 2}, {3}}) })
+}
+
+func TestParseInts(t *testing.T) {
+	req := require.New(t)
+	parsed, err := ParseInts([]string{"1", " -2", "30 "})
+	req.Nil(err)
+	req.Equal([]int{1, -2, 30}, parsed)
+
+	parsed, err = ParseInts([]string{"1", "2", "three", "x"})
+	req.Nil(parsed)
+	req.EqualError(err, `line 3: strconv.Atoi: parsing "three": invalid syntax`)
+}
+
+func TestParseFloats(t *testing.T) {
+	req := require.New(t)
+	parsed, err := ParseFloats([]string{"1.5", "-2", "3e2"})
+	req.Nil(err)
+	req.Equal([]float64{1.5, -2, 300}, parsed)
+
+	parsed, err = ParseFloats([]string{"1.5", ""})
+	req.Nil(parsed)
+	req.ErrorContains(err, "line 2: ")
+}
+
 func TestTri(t *testing.T) {
 	req := require.New(t)
 	req.Equal(13, Tri(13 < 14, 13, 14))
 	req.Equal(14, Tri(14 < 13, 13, 14))
 }
 
+func TestApplyIf(t *testing.T) {
+	req := require.New(t)
+	req.Equal("LOUD", ApplyIf("loud", true, strings.ToUpper))
+	req.Equal("quiet", ApplyIf("quiet", false, strings.ToUpper))
+}
+
 func TestMap(t *testing.T) {
 	double := func(i int) int { return 2 * i }
 	require.Equal(
@@ -126,6 +971,83 @@ func ExampleMap() {
 	// Output: [item: 1 item: 2 item: 4]
 }
 
+func TestMapErr(t *testing.T) {
+	req := require.New(t)
+	mapped, err := MapErr([]string{"1", "2", "3"}, strconv.Atoi)
+	req.Nil(err)
+	req.Equal([]int{1, 2, 3}, mapped)
+
+	calls := 0
+	mapped, err = MapErr([]string{"1", "two", "3"}, func(s string) (int, error) {
+		calls++
+		return strconv.Atoi(s)
+	})
+	req.Nil(mapped)
+	req.ErrorIs(err, strconv.ErrSyntax)
+	req.EqualError(err, `index 1: strconv.Atoi: parsing "two": invalid syntax`)
+	req.Equal(2, calls, "stopped at the failure")
+}
+
+func TestPartition(t *testing.T) {
+	req := require.New(t)
+	s := []int{5, 2, 8, 3, 1, 4}
+	even := func(i int) bool { return i%2 == 0 }
+	evens, odds := Partition(s, even)
+	req.Equal([]int{2, 8, 4}, evens)
+	req.Equal([]int{5, 3, 1}, odds)
+	reconstructed := Map(s, func(i int) int {
+		var next int
+		if even(i) {
+			next, evens = evens[0], evens[1:]
+		} else {
+			next, odds = odds[0], odds[1:]
+		}
+		return next
+	})
+	req.Equal(s, reconstructed, "order preserved")
+
+	evens, odds = Partition(nil, even)
+	req.Nil(evens)
+	req.Nil(odds)
+}
+
+func TestFlatMap(t *testing.T) {
+	divisors := func(n int) []int {
+		var found []int
+		for i := 1; i <= n; i++ {
+			if n%i == 0 {
+				found = append(found, i)
+			}
+		}
+		return found
+	}
+	req := require.New(t)
+	req.Equal([]int{1, 1, 2, 1, 2, 3, 6}, FlatMap([]int{1, 2, 6}, divisors))
+	req.Equal([]int{1, 2}, FlatMap([]int{0, 2, -1}, divisors), "nil results contribute nothing")
+	req.Nil(FlatMap(nil, divisors))
+}
+
+func ExampleFlatMap() {
+	fmt.Print(
+		FlatMap(
+			[]string{"a b", "c"},
+			strings.Fields))
+	// Output: [a b c]
+}
+
+type celsius float64
+
+func (v celsius) String() string {
+	return fmt.Sprintf("%.1f°C", float64(v))
+}
+
+func TestJoin(t *testing.T) {
+	req := require.New(t)
+	req.Equal("1, 2, 3", Join([]int{1, 2, 3}, ", "))
+	req.Equal("-1.5°C|20.0°C", Join([]celsius{-1.5, 20}, "|"))
+	req.Equal("", Join([]int{}, ","))
+}
+
 func TestFilter(t *testing.T) {
 	require.Equal(
 		t,
@@ -145,6 +1067,247 @@ func ExampleFilter() {
 	// Output: [1 3 5]
 }
 
+func TestReduce(t *testing.T) {
+	req := require.New(t)
+	req.Equal(10, Reduce([]int{1, 2, 3, 4}, 0, func(acc, item int) int { return acc + item }))
+	req.Equal(
+		"a-b-c",
+		Reduce([]string{"b", "c"}, "a", func(acc, item string) string { return acc + "-" + item }))
+	req.Equal(
+		map[string]int{"a": 1, "bb": 2},
+		Reduce(
+			[]string{"a", "bb"},
+			map[string]int{},
+			func(acc map[string]int, item string) map[string]int {
+				acc[item] = len(item)
+				return acc
+			}))
+	req.Equal(7, Reduce(nil, 7, func(acc, item int) int { return acc + item }))
+}
+
+func ExampleReduce() {
+	fmt.Print(
+		Reduce(
+			[]int{1, 2, 4},
+			0,
+			func(acc, item int) int {
+				return acc + item
+			}))
+	// Output: 7
+}
+
+func TestReduceWhile(t *testing.T) {
+	req := require.New(t)
+	calls := 0
+	budget := 10
+	sum := ReduceWhile([]int{3, 4, 2, 5, 1}, 0, func(acc, item int) (int, bool) {
+		calls++
+		if acc+item > budget {
+			return acc, false
+		}
+		return acc + item, true
+	})
+	req.Equal(9, sum)
+	req.Equal(4, calls, "stopped at the item exceeding budget")
+	req.Equal(
+		"init",
+		ReduceWhile(nil, "init", func(_ string, _ int) (string, bool) { return "", true }))
+}
+
+func TestSafeDiv(t *testing.T) {
+	req := require.New(t)
+	quotient, ok := SafeDiv(10, 3)
+	req.True(ok)
+	req.Equal(3, quotient)
+	quotient, ok = SafeDiv(10, 0)
+	req.False(ok)
+	req.Equal(0, quotient)
+
+	floatQuotient, ok := SafeDiv(1.0, 4)
+	req.True(ok)
+	req.Equal(0.25, floatQuotient)
+	_, ok = SafeDiv(1.0, 0)
+	req.False(ok)
+}
+
+func TestIterate(t *testing.T) {
+	req := require.New(t)
+	calls := 0
+	double := func(i int) int {
+		calls++
+		return 2 * i
+	}
+	req.Equal(3, Iterate(3, 0, double))
+	req.Equal(0, calls)
+	req.Equal(24, Iterate(3, 3, double))
+	req.Equal(3, calls)
+}
+
+func TestSumBy(t *testing.T) {
+	type order struct {
+		id     string
+		amount float64
+		items  int
+	}
+	orders := []order{
+		{id: "a", amount: 1.5, items: 1},
+		{id: "b", amount: 2.25, items: 3},
+		{id: "c", amount: 10, items: 2},
+	}
+	req := require.New(t)
+	req.Equal(13.75, SumBy(orders, func(o order) float64 { return o.amount }))
+	req.Equal(6, SumBy(orders, func(o order) int { return o.items }))
+	req.Equal(0, SumBy(nil, func(o order) int { return o.items }))
+}
+
+func TestFirstLast(t *testing.T) {
+	req := require.New(t)
+	assertBoundary := func(f func([]string) (string, bool), s []string, expected string) {
+		item, ok := f(s)
+		req.Equal(expected, item)
+		req.Equal(len(s) > 0, ok)
+	}
+	assertBoundary(First[string], nil, "")
+	assertBoundary(Last[string], []string{}, "")
+	assertBoundary(First[string], []string{"a"}, "a")
+	assertBoundary(Last[string], []string{"a"}, "a")
+	assertBoundary(First[string], []string{"a", "b", "c"}, "a")
+	assertBoundary(Last[string], []string{"a", "b", "c"}, "c")
+}
+
+func TestContains(t *testing.T) {
+	req := require.New(t)
+	req.True(Contains([]string{"a", "b"}, "b"))
+	req.False(Contains([]string{"a", "b"}, "c"))
+	req.False(Contains(nil, "a"))
+}
+
+func TestIndexOf(t *testing.T) {
+	req := require.New(t)
+	req.Equal(1, IndexOf([]int{5, 6, 7, 6}, 6))
+	req.Equal(-1, IndexOf([]int{5, 6}, 8))
+	req.Equal(-1, IndexOf([]int{}, 8))
+}
+
+func TestFind(t *testing.T) {
+	req := require.New(t)
+	long := func(s string) bool { return len(s) > 2 }
+	found, ok := Find([]string{"a", "bcd", "efg"}, long)
+	req.True(ok)
+	req.Equal("bcd", found)
+	found, ok = Find([]string{"a", "bc"}, long)
+	req.False(ok)
+	req.Equal("", found)
+	_, ok = Find(nil, long)
+	req.False(ok)
+}
+
+func TestAnyAllNone(t *testing.T) {
+	type param struct {
+		name string
+		s    []int
+		any  bool
+		all  bool
+		none bool
+	}
+	positive := func(i int) bool { return i > 0 }
+	run := func(p param) {
+		t.Run(p.name, func(t *testing.T) {
+			req := require.New(t)
+			req.Equal(p.any, Any(p.s, positive), "any")
+			req.Equal(p.all, All(p.s, positive), "all")
+			req.Equal(p.none, None(p.s, positive), "none")
+		})
+	}
+	run(param{name: "nil", s: nil, any: false, all: true, none: true})
+	run(param{name: "empty", s: []int{}, any: false, all: true, none: true})
+	run(param{name: "all match", s: []int{1, 2}, any: true, all: true, none: false})
+	run(param{name: "some match", s: []int{-1, 2}, any: true, all: false, none: false})
+	run(param{name: "none match", s: []int{-1, 0}, any: false, all: false, none: true})
+
+	t.Run("short-circuit", func(t *testing.T) {
+		calls := 0
+		counted := func(i int) bool {
+			calls++
+			return positive(i)
+		}
+		Any([]int{1, 2, 3}, counted)
+		require.Equal(t, 1, calls)
+	})
+}
+
+func TestAt(t *testing.T) {
+	req := require.New(t)
+	s := []string{"a", "b", "c"}
+	assertAt := func(i int, expected string, expectedOk bool) {
+		item, ok := At(s, i)
+		req.Equal(expected, item, i)
+		req.Equal(expectedOk, ok, i)
+	}
+	assertAt(-1, "", false)
+	assertAt(0, "a", true)
+	assertAt(2, "c", true)
+	assertAt(3, "", false)
+}
+
+func TestIsZero(t *testing.T) {
+	type point struct {
+		x int
+		y int
+	}
+	req := require.New(t)
+	req.True(IsZero(""))
+	req.False(IsZero("a"))
+	req.True(IsZero(0))
+	req.False(IsZero(-1))
+	req.True(IsZero(point{}))
+	req.False(IsZero(point{y: 1}))
+	req.Equal([]string{"a", "b"}, Filter([]string{"", "a", "", "b"}, NonZero[string]))
+	req.Equal([]int{1, 2}, Filter([]int{0, 1, 0, 2}, NonZero[int]))
+}
+
+func TestCloneSlice(t *testing.T) {
+	req := require.New(t)
+	source := []string{"c", "a", "b"}
+	cloned := CloneSlice(source)
+	sort.Strings(cloned)
+	req.Equal([]string{"a", "b", "c"}, cloned)
+	req.Equal([]string{"c", "a", "b"}, source, "source untouched")
+	req.Nil(CloneSlice[int](nil))
+}
+
+func TestThrottledMap(t *testing.T) {
+	t.Run("limit", func(t *testing.T) {
+		req := require.New(t)
+		var running, peak atomic.Int32
+		mapped, err := ThrottledMap(
+			[]int{1, 2, 3, 4, 5, 6, 7, 8},
+			3,
+			func(i int) (string, error) {
+				current := running.Add(1)
+				defer running.Add(-1)
+				for {
+					highest := peak.Load()
+					if current <= highest || peak.CompareAndSwap(highest, current) {
+						break
+					}
+				}
+				time.Sleep(time.Millisecond)
+				return strconv.Itoa(i), nil
+			})
+		req.Nil(err)
+		req.Equal([]string{"1", "2", "3", "4", "5", "6", "7", "8"}, mapped)
+		req.LessOrEqual(peak.Load(), int32(3))
+	})
+
+	t.Run("error", func(t *testing.T) {
+		req := require.New(t)
+		mapped, err := ThrottledMap([]string{"1", "x", "3", "y"}, 2, strconv.Atoi)
+		req.Nil(mapped)
+		req.ErrorContains(err, `"x"`)
+	})
+}
+
 func TestOrPanic2(t *testing.T) {
 	req := require.New(t)
 	req.Equal("wow", OrPanic2("wow", nil)(""))
@@ -153,6 +1316,19 @@ func TestOrPanic2(t *testing.T) {
 		func() { OrPanic2("", errors.New("turn"))("killed") })
 }
 
+func TestMust(t *testing.T) {
+	req := require.New(t)
+	req.Equal(7, Must(strconv.Atoi("7")))
+	req.PanicsWithError(
+		`strconv.Atoi: parsing "x": invalid syntax`,
+		func() { Must(strconv.Atoi("x")) })
+}
+
+func ExampleMust() {
+	fmt.Println(Must(strconv.Atoi("42")))
+	// Output: 42
+}
+
 func ExampleOrPanic2() {
 	defer func() {
 		if r := recover(); r != nil {
@@ -179,6 +1355,57 @@ func ExampleOrPanic2() {
 	// Message: nope. Error: can't divide with zero.
 }
 
+func TestOrElse(t *testing.T) {
+	succeed := func(value string) func() (string, error) {
+		return func() (string, error) { return value, nil }
+	}
+	fail := func(message string) func() (string, error) {
+		return func() (string, error) { return "", errors.New(message) }
+	}
+	mustNotCall := func() (string, error) {
+		panic("fallback called")
+	}
+
+	t.Run("primary", func(t *testing.T) {
+		value, err := OrElse(succeed("fast"), mustNotCall)
+		require.Nil(t, err)
+		require.Equal(t, "fast", value)
+	})
+	t.Run("fallback", func(t *testing.T) {
+		value, err := OrElse(fail("fast"), succeed("slow"))
+		require.Nil(t, err)
+		require.Equal(t, "slow", value)
+	})
+	t.Run("both fail", func(t *testing.T) {
+		_, err := OrElse(fail("fast"), fail("slow"))
+		require.EqualError(t, err, "slow")
+	})
+}
+
+func TestMustOk(t *testing.T) {
+	req := require.New(t)
+	m := map[string]int{"one": 1}
+	lookup := func(key string) (int, bool) {
+		value, ok := m[key]
+		return value, ok
+	}
+	req.Equal(1, MustOk(lookup("one")))
+	req.PanicsWithValue("Not ok.", func() { MustOk(lookup("two")) })
+}
+
+func TestCombineErrors(t *testing.T) {
+	req := require.New(t)
+	req.Nil(CombineErrors())
+	req.Nil(CombineErrors(nil, nil))
+
+	first := errors.New("first")
+	second := errors.New("second")
+	combined := CombineErrors(nil, first, nil, second)
+	req.ErrorIs(combined, first)
+	req.ErrorIs(combined, second)
+	req.EqualError(combined, "first\nsecond")
+}
+
 func TestNewOption(t *testing.T) {
 	type person struct {
 		name string
@@ -0,0 +1,111 @@
+package gent
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// IntSet is a set of small non-negative integers backed by a bitset.
+// Add, Has, Remove, and Len are O(1), and Union and Intersection operate word by word.
+// Memory use is proportional to the largest possible item, not to the number of items.
+type IntSet struct {
+	words []uint64
+	limit int
+	count int
+}
+
+// NewIntSet creates a new [gent.IntSet] for integers from 0 to limit-1.
+// Panics when limit is negative.
+func NewIntSet(limit int) *IntSet {
+	if limit < 0 {
+		panic(fmt.Sprintf("Invalid limit: %d.", limit))
+	}
+	return &IntSet{words: make([]uint64, (limit+63)/64), limit: limit}
+}
+
+// Add item to the set, return true if it was added.
+// Otherwise it already existed and wasn't added.
+// Panics when item is out of range.
+func (v *IntSet) Add(item int) (added bool) {
+	if !v.inRange(item) {
+		panic(fmt.Sprintf("Item out of range [0, %d): %d.", v.limit, item))
+	}
+	word, bit := item/64, uint64(1)<<(item%64)
+	if v.words[word]&bit != 0 {
+		return
+	}
+	v.words[word] |= bit
+	v.count++
+	return true
+}
+
+// Has checks if item exists in the set.
+func (v *IntSet) Has(item int) bool {
+	return v.inRange(item) && v.words[item/64]&(uint64(1)<<(item%64)) != 0
+}
+
+// Remove removes an item in the set, returns true if it was.
+// I.e. if it existed.
+func (v *IntSet) Remove(item int) (existed bool) {
+	if !v.Has(item) {
+		return
+	}
+	v.words[item/64] &^= uint64(1) << (item % 64)
+	v.count--
+	return true
+}
+
+// Len returns the number of items in the set.
+func (v *IntSet) Len() int {
+	return v.count
+}
+
+// Union returns a new set with the items that exist in either set.
+// Range of the new set is the larger of the two.
+func (v *IntSet) Union(other *IntSet) *IntSet {
+	larger, smaller := v, other
+	if smaller.limit > larger.limit {
+		larger, smaller = smaller, larger
+	}
+	result := NewIntSet(larger.limit)
+	copy(result.words, larger.words)
+	for i, each := range smaller.words {
+		result.words[i] |= each
+	}
+	result.recount()
+	return result
+}
+
+// Intersection returns a new set with the items that exist in both sets.
+// Range of the new set is the smaller of the two.
+func (v *IntSet) Intersection(other *IntSet) *IntSet {
+	result := NewIntSet(min(v.limit, other.limit))
+	for i := range result.words {
+		result.words[i] = v.words[i] & other.words[i]
+	}
+	result.recount()
+	return result
+}
+
+// ToSlice returns a slice with all set items in ascending order.
+func (v *IntSet) ToSlice() []int {
+	items := make([]int, 0, v.count)
+	for i, each := range v.words {
+		for each != 0 {
+			items = append(items, i*64+bits.TrailingZeros64(each))
+			each &= each - 1
+		}
+	}
+	return items
+}
+
+func (v *IntSet) inRange(item int) bool {
+	return item >= 0 && item < v.limit
+}
+
+func (v *IntSet) recount() {
+	v.count = 0
+	for _, each := range v.words {
+		v.count += bits.OnesCount64(each)
+	}
+}
@@ -0,0 +1,64 @@
+package gent
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntSet(t *testing.T) {
+	t.Run("parity with Set", func(t *testing.T) {
+		req := require.New(t)
+		random := rand.New(rand.NewSource(1))
+		set := NewSet[int]()
+		intSet := NewIntSet(200)
+		for i := 0; i < 2000; i++ {
+			item := random.Intn(200)
+			if random.Intn(3) == 0 {
+				req.Equal(set.Remove(item), intSet.Remove(item), "remove %d", item)
+			} else {
+				req.Equal(set.Add(item), intSet.Add(item), "add %d", item)
+			}
+			req.Equal(set.Has(item), intSet.Has(item), "has %d", item)
+			req.Equal(set.Len(), intSet.Len())
+		}
+		expected := set.ToSlice()
+		sort.Ints(expected)
+		req.Equal(expected, intSet.ToSlice())
+	})
+
+	t.Run("range", func(t *testing.T) {
+		req := require.New(t)
+		set := NewIntSet(10)
+		req.False(set.Has(-1))
+		req.False(set.Has(10))
+		req.False(set.Remove(10))
+		req.True(set.Add(9))
+		req.PanicsWithValue("Item out of range [0, 10): 10.", func() { set.Add(10) })
+		req.Panics(func() { set.Add(-1) })
+		req.PanicsWithValue("Invalid limit: -200.", func() { NewIntSet(-200) })
+		req.Equal(0, NewIntSet(0).Len())
+	})
+
+	t.Run("Union and Intersection", func(t *testing.T) {
+		req := require.New(t)
+		evens, threes := NewIntSet(1000), NewIntSet(500)
+		for i := 0; i < 1000; i += 2 {
+			evens.Add(i)
+		}
+		for i := 0; i < 500; i += 3 {
+			threes.Add(i)
+		}
+		union := evens.Union(threes)
+		intersection := evens.Intersection(threes)
+		for i := 0; i < 1000; i++ {
+			req.Equal(i%2 == 0 || (i < 500 && i%3 == 0), union.Has(i), "union %d", i)
+			req.Equal(i < 500 && i%6 == 0, intersection.Has(i), "intersection %d", i)
+		}
+		req.Equal(500+83, union.Len())
+		req.Equal(84, intersection.Len())
+		req.Equal(500, evens.Len(), "source untouched")
+	})
+}
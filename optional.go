@@ -0,0 +1,42 @@
+package gent
+
+// Optional is a value that may or may not be present.
+// Create it with [gent.Some] or [gent.Empty], the zero value is also empty.
+type Optional[T any] struct {
+	value   T
+	present bool
+}
+
+// Some creates a present [gent.Optional].
+func Some[T any](v T) Optional[T] {
+	return Optional[T]{value: v, present: true}
+}
+
+// Empty creates an absent [gent.Optional].
+// It would be None but that's already taken by [gent.None].
+func Empty[T any]() Optional[T] {
+	return Optional[T]{}
+}
+
+// Get returns the value and true if it's present.
+func (v Optional[T]) Get() (T, bool) {
+	return v.value, v.present
+}
+
+// OrElse returns the value if it's present, otherwise def.
+func (v Optional[T]) OrElse(def T) T {
+	return Tri(v.present, v.value, def)
+}
+
+// IsPresent returns true when the value is present.
+func (v Optional[T]) IsPresent() bool {
+	return v.present
+}
+
+// MapOptional maps the value of o with f if it's present.
+func MapOptional[T, U any](o Optional[T], f func(T) U) Optional[U] {
+	if !o.present {
+		return Empty[U]()
+	}
+	return Some(f(o.value))
+}
@@ -0,0 +1,46 @@
+package gent
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptional(t *testing.T) {
+	t.Run("present", func(t *testing.T) {
+		req := require.New(t)
+		o := Some(0)
+		req.True(o.IsPresent())
+		value, ok := o.Get()
+		req.True(ok)
+		req.Equal(0, value)
+		req.Equal(0, o.OrElse(7))
+	})
+
+	t.Run("absent", func(t *testing.T) {
+		req := require.New(t)
+		for _, each := range []Optional[int]{Empty[int](), {}} {
+			req.False(each.IsPresent())
+			_, ok := each.Get()
+			req.False(ok)
+			req.Equal(7, each.OrElse(7))
+		}
+	})
+
+	t.Run("MapOptional", func(t *testing.T) {
+		req := require.New(t)
+		mapped := MapOptional(Some(12), strconv.Itoa)
+		req.Equal(Some("12"), mapped)
+		req.Equal("12", mapped.OrElse("none"))
+
+		called := false
+		absent := MapOptional(Empty[int](), func(i int) string {
+			called = true
+			return strconv.Itoa(i)
+		})
+		req.False(called)
+		req.False(absent.IsPresent())
+		req.Equal("none", absent.OrElse("none"))
+	})
+}
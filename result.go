@@ -0,0 +1,34 @@
+package gent
+
+// Result is a value paired with the error of the computation that produced it.
+// Create it with [gent.Ok] or [gent.Err].
+type Result[T any] struct {
+	value T
+	err   error
+}
+
+// Ok creates a successful [gent.Result].
+func Ok[T any](v T) Result[T] {
+	return Result[T]{value: v}
+}
+
+// Err creates a failed [gent.Result].
+func Err[T any](e error) Result[T] {
+	return Result[T]{err: e}
+}
+
+// Unwrap returns the value or panics when there's an error.
+// Panic message follows [gent.OrPanic2].
+func (v Result[T]) Unwrap() T {
+	return OrPanic2(v.value, v.err)("Unwrap")
+}
+
+// UnwrapOr returns the value or def when there's an error.
+func (v Result[T]) UnwrapOr(def T) T {
+	return Tri(v.err == nil, v.value, def)
+}
+
+// IsOk returns true when there's no error.
+func (v Result[T]) IsOk() bool {
+	return v.err == nil
+}
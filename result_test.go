@@ -0,0 +1,32 @@
+package gent
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResult(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		req := require.New(t)
+		r := Ok("value")
+		req.True(r.IsOk())
+		req.Equal("value", r.Unwrap())
+		req.Equal("value", r.UnwrapOr("def"))
+	})
+
+	t.Run("error", func(t *testing.T) {
+		req := require.New(t)
+		r := Err[string](errors.New("boom"))
+		req.False(r.IsOk())
+		req.Equal("def", r.UnwrapOr("def"))
+		req.PanicsWithValue("Message: Unwrap. Error: boom.", func() { r.Unwrap() })
+	})
+
+	t.Run("in slice", func(t *testing.T) {
+		req := require.New(t)
+		results := []Result[int]{Ok(1), Err[int](errors.New("x")), Ok(3)}
+		req.Equal([]int{1, 0, 3}, Map(results, func(r Result[int]) int { return r.UnwrapOr(0) }))
+	})
+}
@@ -0,0 +1,74 @@
+package gent
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+// RetryConfig configures [gent.RetryBackoff].
+type RetryConfig struct {
+	clock    Clock
+	maxDelay time.Duration
+}
+
+// WithRetryClock sets the clock used for waiting between attempts, [gent.SystemClock] by default.
+func WithRetryClock(clock Clock) func(*RetryConfig) {
+	return func(c *RetryConfig) {
+		c.clock = clock
+	}
+}
+
+// WithMaxDelay caps the delay between attempts, 30 seconds by default.
+func WithMaxDelay(maxDelay time.Duration) func(*RetryConfig) {
+	return func(c *RetryConfig) {
+		c.maxDelay = maxDelay
+	}
+}
+
+// RetryBackoff calls f until it succeeds or it has been called attempts times.
+// Delay between attempts starts from base and doubles each time up to a cap.
+// Up to half of the delay is added as jitter.
+// Retrying stops when ctx is cancelled, in which case ctx's error is returned.
+// Otherwise the error of the last attempt is returned.
+// Panics when attempts is less than 1.
+func RetryBackoff(
+	ctx context.Context,
+	attempts int,
+	base time.Duration,
+	f func() error,
+	options ...func(*RetryConfig),
+) (err error) {
+	if attempts < 1 {
+		panic(fmt.Sprintf("Invalid attempt count: %d.", attempts))
+	}
+	config := NewOption(
+		RetryConfig{clock: SystemClock{}, maxDelay: 30 * time.Second},
+		options...)
+	delay := min(base, config.maxDelay)
+	for i := 0; i < attempts; i++ {
+		if i > 0 {
+			select {
+			case <-config.clock.After(delay + jitter(delay)):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			delay = min(delay*2, config.maxDelay)
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if err = f(); err == nil {
+			return
+		}
+	}
+	return
+}
+
+func jitter(delay time.Duration) time.Duration {
+	if delay < 2 {
+		return 0
+	}
+	return rand.N(delay / 2)
+}
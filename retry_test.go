@@ -0,0 +1,99 @@
+package gent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Clock that records the delays and doesn't wait.
+type recordingClock struct {
+	delays []time.Duration
+}
+
+func (*recordingClock) Now() time.Time {
+	return time.Time{}
+}
+
+func (v *recordingClock) After(d time.Duration) <-chan time.Time {
+	v.delays = append(v.delays, d)
+	c := make(chan time.Time, 1)
+	c <- time.Time{}
+	return c
+}
+
+func TestRetryBackoff(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		req := require.New(t)
+		clock := &recordingClock{}
+		calls := 0
+		err := RetryBackoff(context.Background(), 10, time.Second, func() error {
+			calls++
+			return Tri(calls < 5, errors.New("flaky"), nil)
+		}, WithRetryClock(clock), WithMaxDelay(4*time.Second))
+		req.Nil(err)
+		req.Equal(5, calls)
+		req.Len(clock.delays, 4)
+		for i, each := range []time.Duration{1, 2, 4, 4} {
+			expected := each * time.Second
+			req.GreaterOrEqual(clock.delays[i], expected, "delay %d", i)
+			req.Less(clock.delays[i], expected+expected/2, "jitter %d", i)
+		}
+	})
+
+	t.Run("attempts exhausted", func(t *testing.T) {
+		req := require.New(t)
+		calls := 0
+		err := RetryBackoff(context.Background(), 3, time.Second, func() error {
+			calls++
+			return errors.New("down")
+		}, WithRetryClock(&recordingClock{}))
+		req.EqualError(err, "down")
+		req.Equal(3, calls)
+	})
+
+	t.Run("base over cap", func(t *testing.T) {
+		req := require.New(t)
+		clock := &recordingClock{}
+		err := RetryBackoff(context.Background(), 2, time.Minute, func() error {
+			return errors.New("down")
+		}, WithRetryClock(clock), WithMaxDelay(time.Second))
+		req.EqualError(err, "down")
+		req.Len(clock.delays, 1)
+		req.Less(clock.delays[0], time.Second+time.Second/2)
+	})
+
+	t.Run("invalid attempts", func(t *testing.T) {
+		req := require.New(t)
+		calls := 0
+		for _, each := range []int{0, -1} {
+			req.PanicsWithValue(
+				fmt.Sprintf("Invalid attempt count: %d.", each),
+				func() {
+					_ = RetryBackoff(context.Background(), each, time.Second, func() error {
+						calls++
+						return nil
+					})
+				})
+		}
+		req.Equal(0, calls)
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		req := require.New(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		clock := &fakeClock{after: make(chan time.Time)}
+		calls := 0
+		err := RetryBackoff(ctx, 10, time.Second, func() error {
+			calls++
+			cancel()
+			return errors.New("down")
+		}, WithRetryClock(clock))
+		req.ErrorIs(err, context.Canceled)
+		req.Equal(1, calls)
+	})
+}
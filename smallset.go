@@ -0,0 +1,121 @@
+package gent
+
+import (
+	"cmp"
+	"slices"
+)
+
+// SmallSet is a set backed by a sorted slice.
+// It has the same core API as [gent.Set] but for small sets it's lighter than a map.
+// Lookups use binary search and insertions and removals move the items after the position,
+// so don't use it for large sets.
+type SmallSet[T cmp.Ordered] struct {
+	items []T
+}
+
+// NewSmallSet creates a new [gent.SmallSet].
+func NewSmallSet[T cmp.Ordered](items ...T) *SmallSet[T] {
+	set := &SmallSet[T]{}
+	for _, each := range items {
+		set.Add(each)
+	}
+	return set
+}
+
+// Add item to the set, return true if it was added.
+// Otherwise it already existed and wasn't added.
+func (v *SmallSet[T]) Add(item T) (added bool) {
+	i, existed := slices.BinarySearch(v.items, item)
+	if existed {
+		return
+	}
+	added = true
+	v.items = slices.Insert(v.items, i, item)
+	return
+}
+
+// Clear the set, remove all items.
+// Returns the number of removed items.
+func (v *SmallSet[T]) Clear() (removed int) {
+	removed = len(v.items)
+	v.items = nil
+	return
+}
+
+// Equal returns true when the sets contain the exact same items.
+// Nil set is equal to an empty set.
+func (v *SmallSet[T]) Equal(s *SmallSet[T]) bool {
+	return slices.Equal(v.slice(), s.slice())
+}
+
+// Contains checks if item exists in the set.
+// Alias for [gent.SmallSet.Has].
+func (v *SmallSet[T]) Contains(item T) bool {
+	return v.Has(item)
+}
+
+// Has checks if item exists in the set.
+func (v *SmallSet[T]) Has(item T) bool {
+	_, found := slices.BinarySearch(v.slice(), item)
+	return found
+}
+
+// ForEach iterates all items in ascending order, calls f for each item, stops if stop is called.
+// Use [gent.SmallSet.ForEachAll] if there's no need to stop iteration.
+func (v *SmallSet[T]) ForEach(f func(each T, stop func())) {
+	breaker := false
+	for _, each := range v.slice() {
+		f(each, func() {
+			breaker = true
+		})
+		if breaker {
+			break
+		}
+	}
+}
+
+// ForEachAll iterates all items in ascending order and calls f for each item.
+// Use [gent.SmallSet.ForEach] if you need to stop iteration.
+func (v *SmallSet[T]) ForEachAll(f func(each T)) {
+	for _, each := range v.slice() {
+		f(each)
+	}
+}
+
+// Len returns the number of items in the set.
+func (v *SmallSet[T]) Len() int {
+	return len(v.slice())
+}
+
+// Count returns the number of items in the set.
+// Alias for [gent.SmallSet.Len].
+func (v *SmallSet[T]) Count() int {
+	return v.Len()
+}
+
+// Remove removes an item in the set, returns true if it was.
+// I.e. if it existed.
+func (v *SmallSet[T]) Remove(item T) (existed bool) {
+	if v == nil {
+		return
+	}
+	var i int
+	if i, existed = slices.BinarySearch(v.items, item); existed {
+		v.items = slices.Delete(v.items, i, i+1)
+	}
+	return
+}
+
+// ToSlice returns a slice with all set items in ascending order.
+// Set itself doesn't change.
+func (v *SmallSet[T]) ToSlice() []T {
+	return append([]T{}, v.slice()...)
+}
+
+// Items of the set, treating nil set as empty.
+func (v *SmallSet[T]) slice() []T {
+	if v == nil {
+		return nil
+	}
+	return v.items
+}
@@ -0,0 +1,72 @@
+package gent
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSmallSet(t *testing.T) {
+	t.Run("parity with Set", func(t *testing.T) {
+		req := require.New(t)
+		random := rand.New(rand.NewSource(1))
+		set := NewSet[int]()
+		small := NewSmallSet[int]()
+		for i := 0; i < 1000; i++ {
+			item := random.Intn(50)
+			if random.Intn(3) == 0 {
+				req.Equal(set.Remove(item), small.Remove(item), "remove %d", item)
+			} else {
+				req.Equal(set.Add(item), small.Add(item), "add %d", item)
+			}
+			req.Equal(set.Has(item), small.Has(item), "has %d", item)
+			req.Equal(set.Len(), small.Len())
+		}
+		expected := set.ToSlice()
+		sort.Ints(expected)
+		req.Equal(expected, small.ToSlice())
+	})
+
+	t.Run("ToSlice", func(t *testing.T) {
+		req := require.New(t)
+		set := NewSmallSet("m", "o", "o", "n", "a")
+		req.Equal([]string{"a", "m", "n", "o"}, set.ToSlice())
+		req.Equal(4, set.Count())
+		req.True(set.Contains("n"))
+	})
+
+	t.Run("Equal", func(t *testing.T) {
+		req := require.New(t)
+		req.True(NewSmallSet(3, 1, 2).Equal(NewSmallSet(1, 2, 3)))
+		req.False(NewSmallSet(3, 1, 2).Equal(NewSmallSet(1, 2)))
+		req.True(NewSmallSet[int]().Equal(nil))
+	})
+
+	t.Run("ForEach", func(t *testing.T) {
+		req := require.New(t)
+		set := NewSmallSet(3, 1, 2)
+		visited := []int{}
+		set.ForEach(func(each int, stop func()) {
+			visited = append(visited, each)
+			if each == 2 {
+				stop()
+			}
+		})
+		req.Equal([]int{1, 2}, visited)
+
+		visited = []int{}
+		set.ForEachAll(func(each int) {
+			visited = append(visited, each)
+		})
+		req.Equal([]int{1, 2, 3}, visited)
+	})
+
+	t.Run("Clear", func(t *testing.T) {
+		req := require.New(t)
+		set := NewSmallSet(1, 2, 3)
+		req.Equal(3, set.Clear())
+		req.Equal(0, set.Len())
+	})
+}
@@ -3,29 +3,54 @@ package snap
 
 import (
 	"bytes"
+	"compress/gzip"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"regexp"
+	"runtime"
+	"slices"
 	"strings"
+	"testing"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/stretchr/testify/require"
+
+	"github.com/denarced/gent"
 )
 
 var (
 	nonSafeFilenamePattern = regexp.MustCompile(`[^0-9a-zA-Z-._]`)
+	// Variable so that tests can simulate failures.
+	renameFile = os.Rename
 )
 
+const gzipSuffix = ".gz"
+
 // A SnapshotSuite is a suite of snapshot tests with a shared directory for the snapshot files.
 // It is made of [snap.Snapshot]s.
 type SnapshotSuite struct {
 	rootDir string
+	// Used by snapshots created without a VerifyFunc.
+	equal VerifyFunc
 }
 
 // NewSnapshotSuite creates a [snap.SnapshotSuite] with a root directory.
 // Usually it's under "testdata".
-func NewSnapshotSuite(rootDir string) *SnapshotSuite {
-	return &SnapshotSuite{rootDir: rootDir}
+func NewSnapshotSuite(rootDir string, options ...func(*SnapshotSuite)) *SnapshotSuite {
+	suite := gent.NewOption(SnapshotSuite{rootDir: rootDir}, options...)
+	return &suite
+}
+
+// WithDefaultVerify sets the VerifyFunc for snapshots that are created with nil VerifyFunc.
+func WithDefaultVerify(equal VerifyFunc) func(*SnapshotSuite) {
+	return func(s *SnapshotSuite) {
+		s.equal = equal
+	}
 }
 
 // VerifyFunc is used to assert that snapshot matches to the string that code produced.
@@ -39,6 +64,28 @@ type Snapshot struct {
 	filep  string
 	verify bool
 	equal  VerifyFunc
+	// Applied to both the snapshot and the view before they're compared.
+	normalizers []func(string) string
+	// Write the view and diff next to the snapshot file on mismatch.
+	sidecars bool
+	// Snapshot file is gzip compressed.
+	gzip bool
+	// Write metadata next to the snapshot file.
+	metadata bool
+	// Applied to the snapshot after it's read.
+	readTransform func(string) string
+	// Applied to the view before it's written.
+	writeTransform func(string) string
+	// Called with a diff when the snapshot is updated in non-verify mode.
+	report func(name, diff string)
+	// Source of the metadata timestamp.
+	clock gent.Clock
+}
+
+// Metadata of a snapshot's latest write, see [snap.WithMetadata].
+type Metadata struct {
+	Written   time.Time `json:"written"`
+	GoVersion string    `json:"goVersion"`
 }
 
 // NewSnapshot creates a snapshot.
@@ -50,15 +97,221 @@ type Snapshot struct {
 // content produced by the tested code is written.
 // And finally, when verify is true and the snapshot file exists,
 // equal function is used to assert equality.
-func (v *SnapshotSuite) NewSnapshot(name string, verify bool, equal VerifyFunc) *Snapshot {
-	return &Snapshot{
-		Name:   name,
-		filep:  v.deriveSnapshotFilep(name),
-		verify: verify,
-		equal:  equal,
+// When equal is nil, the suite's default set with [snap.WithDefaultVerify] is used.
+// Options, e.g. [snap.WithForwardSlashes], can be used to alter the behavior.
+func (v *SnapshotSuite) NewSnapshot(
+	name string,
+	verify bool,
+	equal VerifyFunc,
+	options ...func(*Snapshot),
+) *Snapshot {
+	snapshot := gent.NewOption(
+		Snapshot{
+			Name:   name,
+			filep:  v.deriveSnapshotFilep(name),
+			verify: verify,
+			equal:  gent.Tri(equal == nil, v.equal, equal),
+			clock:  gent.SystemClock{},
+		},
+		options...)
+	if snapshot.gzip && !strings.HasSuffix(snapshot.filep, gzipSuffix) {
+		snapshot.filep += gzipSuffix
+	}
+	return &snapshot
+}
+
+// NewSnapshotForT creates a snapshot for test t.
+// Name is derived from t's name with [snap.ToSafeFilename]
+// and snapshot is asserted with [require.Assertions.Equal].
+// Otherwise it's like [snap.SnapshotSuite.NewSnapshot].
+func (v *SnapshotSuite) NewSnapshotForT(
+	t *testing.T,
+	verify bool,
+	options ...func(*Snapshot),
+) *Snapshot {
+	req := require.New(t)
+	equal := func(expected, actual, message string) {
+		req.Equal(expected, actual, message)
+	}
+	return v.NewSnapshot(ToSafeFilename(t.Name()), verify, equal, options...)
+}
+
+// WithFilepath overrides the snapshot's filepath that's otherwise derived from the name.
+// Use it to e.g. point to a shared fixture outside the suite's root directory.
+func WithFilepath(filep string) func(*Snapshot) {
+	return func(s *Snapshot) {
+		s.filep = filep
+	}
+}
+
+// WithForwardSlashes converts backslashes to forward slashes before comparison.
+// Use it when the view contains filepaths and the snapshot is shared between Windows and Unix.
+func WithForwardSlashes() func(*Snapshot) {
+	return withReplacement(`\`, "/")
+}
+
+// WithBackslashes converts forward slashes to backslashes before comparison.
+// It's the reverse of [snap.WithForwardSlashes].
+func WithBackslashes() func(*Snapshot) {
+	return withReplacement("/", `\`)
+}
+
+// IgnoreLinesMatching drops lines that match any of the patterns before comparison.
+// Use it to exclude volatile lines such as ones with timestamps.
+func IgnoreLinesMatching(patterns ...*regexp.Regexp) func(*Snapshot) {
+	return func(s *Snapshot) {
+		s.normalizers = append(s.normalizers, func(content string) string {
+			lines := gent.Filter(strings.Split(content, "\n"), func(line string) bool {
+				for _, each := range patterns {
+					if each.MatchString(line) {
+						return false
+					}
+				}
+				return true
+			})
+			return strings.Join(lines, "\n")
+		})
+	}
+}
+
+// RegionBetween compares only the lines between the start and end marker lines.
+// Region starts after the first line that contains start and ends before the next line
+// that contains end, or at the end of content if there is none.
+// Content without the start marker is compared as a whole.
+func RegionBetween(start, end string) func(*Snapshot) {
+	return func(s *Snapshot) {
+		s.normalizers = append(s.normalizers, func(content string) string {
+			lines := strings.Split(content, "\n")
+			first := slices.IndexFunc(lines, func(line string) bool {
+				return strings.Contains(line, start)
+			})
+			if first < 0 {
+				return content
+			}
+			region := lines[first+1:]
+			if last := slices.IndexFunc(region, func(line string) bool {
+				return strings.Contains(line, end)
+			}); last >= 0 {
+				region = region[:last]
+			}
+			return strings.Join(region, "\n")
+		})
+	}
+}
+
+// WithMismatchSidecars writes sidecar files next to the snapshot file when verification fails.
+// The view is written to "<name>.actual" and the diff to "<name>.diff".
+// Snapshot file itself is left untouched. Useful for uploading CI artifacts.
+// Sidecars are removed when the snapshot matches again.
+func WithMismatchSidecars() func(*Snapshot) {
+	return func(s *Snapshot) {
+		s.sidecars = true
+	}
+}
+
+// WithGzip stores the snapshot gzip compressed in a file with suffix ".gz".
+// Snapshot is decompressed when read so the comparison is done with the plain content.
+func WithGzip() func(*Snapshot) {
+	return func(s *Snapshot) {
+		s.gzip = true
+	}
+}
+
+// WithReadTransform transforms the snapshot after it's read, before comparison.
+// Unlike normalizers, e.g. [snap.IgnoreLinesMatching], the view is not transformed.
+func WithReadTransform(f func(string) string) func(*Snapshot) {
+	return func(s *Snapshot) {
+		s.readTransform = f
+	}
+}
+
+// WithWriteTransform transforms the view before it's written, e.g. to store a canonical form.
+// The transformation only affects the written snapshot, not the comparison.
+func WithWriteTransform(f func(string) string) func(*Snapshot) {
+	return func(s *Snapshot) {
+		s.writeTransform = f
+	}
+}
+
+// WithMetadata maintains a "<name>.meta" JSON sidecar file with [snap.Metadata].
+// It's updated every time the snapshot is written which helps in diagnosing stale snapshots.
+// Timestamp comes from the clock set with [snap.WithClock].
+func WithMetadata() func(*Snapshot) {
+	return func(s *Snapshot) {
+		s.metadata = true
 	}
 }
 
+// WithUpdateReport calls report with a unified diff of the old and new snapshot
+// whenever Run updates the snapshot in non-verify mode.
+// Unchanged snapshots aren't reported. Use it to preview an update before committing it.
+func WithUpdateReport(report func(name, diff string)) func(*Snapshot) {
+	return func(s *Snapshot) {
+		s.report = report
+	}
+}
+
+// WithClock sets the clock for the timestamp in [snap.Metadata], [gent.SystemClock] by default.
+func WithClock(clock gent.Clock) func(*Snapshot) {
+	return func(s *Snapshot) {
+		s.clock = clock
+	}
+}
+
+func withReplacement(old, replacement string) func(*Snapshot) {
+	return func(s *Snapshot) {
+		s.normalizers = append(s.normalizers, func(content string) string {
+			return strings.ReplaceAll(content, old, replacement)
+		})
+	}
+}
+
+func (v *Snapshot) normalize(content string) string {
+	for _, each := range v.normalizers {
+		content = each(content)
+	}
+	return content
+}
+
+// RemoveEmpty removes empty snapshot files in the suite's root directory.
+// Dotfiles, e.g. ".gitkeep", and "<seriesID>.txt" message files of
+// [snap.RunBubbleTeaSnapshots] are kept since they're not snapshots and an empty one is valid.
+// A "*.txt" file is a message file when its first frame, "<seriesID>_000", exists.
+// Returns the number of removed files. Use it for housekeeping, e.g. in TestMain.
+func (v *SnapshotSuite) RemoveEmpty() (removed int, err error) {
+	entries, err := os.ReadDir(v.rootDir)
+	if err != nil {
+		return
+	}
+	names := gent.NewSetFromSlice(gent.Map(entries, os.DirEntry.Name))
+	for _, each := range entries {
+		if !each.Type().IsRegular() || !isSnapshotFilename(each.Name(), names) {
+			continue
+		}
+		var info os.FileInfo
+		if info, err = each.Info(); err != nil {
+			return
+		}
+		if info.Size() > 0 {
+			continue
+		}
+		if err = os.Remove(filepath.Join(v.rootDir, each.Name())); err != nil {
+			return
+		}
+		removed++
+	}
+	return
+}
+
+// Check whether name is a snapshot and not e.g. a message file, names is the directory listing.
+func isSnapshotFilename(name string, names *gent.Set[string]) bool {
+	if strings.HasPrefix(name, ".") {
+		return false
+	}
+	seriesID, isTxt := strings.CutSuffix(name, ".txt")
+	return !isTxt || !names.Has(frameName(seriesID, 0))
+}
+
 func (v *SnapshotSuite) deriveSnapshotFilep(name string) string {
 	return filepath.Join(v.rootDir, name)
 }
@@ -71,11 +324,126 @@ func (v *Snapshot) read() (string, error) {
 		}
 		return "", err
 	}
+	if v.gzip {
+		return decompress(b)
+	}
 	return string(b), nil
 }
 
-func (v *Snapshot) write(content string) error {
-	return os.WriteFile(v.filep, []byte(content), 0644)
+func (v *Snapshot) write(content string) (err error) {
+	if v.gzip {
+		if content, err = compress(content); err != nil {
+			return
+		}
+	}
+	if err = writeFile(v.filep, content); err != nil || !v.metadata {
+		return
+	}
+	return v.writeMetadata()
+}
+
+func (v *Snapshot) writeMetadata() error {
+	b, err := json.MarshalIndent(
+		Metadata{Written: v.clock.Now(), GoVersion: runtime.Version()},
+		"",
+		"  ")
+	if err != nil {
+		return err
+	}
+	return writeFile(v.filep+".meta", string(b))
+}
+
+func compress(content string) (string, error) {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write([]byte(content)); err != nil {
+		return "", err
+	}
+	if err := writer.Close(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func decompress(b []byte) (string, error) {
+	if len(b) == 0 {
+		return "", nil
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+	decompressed, err := io.ReadAll(reader)
+	if err != nil {
+		return "", err
+	}
+	return string(decompressed), nil
+}
+
+func unifiedDiff(a, b, fromFile, toFile string) (string, error) {
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(a),
+		B:        difflib.SplitLines(b),
+		FromFile: fromFile,
+		ToFile:   toFile,
+		Context:  3,
+	})
+}
+
+func (v *Snapshot) writeSidecars(view, expected, actual string) error {
+	diff, err := unifiedDiff(expected, actual, "expected", "actual")
+	if err != nil {
+		return err
+	}
+	if err = writeFile(v.filep+".actual", view); err != nil {
+		return err
+	}
+	return writeFile(v.filep+".diff", diff)
+}
+
+// Remove sidecars left behind by an earlier mismatch.
+func (v *Snapshot) removeSidecars() error {
+	for _, each := range []string{v.filep + ".actual", v.filep + ".diff"} {
+		if err := os.Remove(each); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Write content to a temporary file and rename it over filep.
+// That way an interrupted write can't leave a half-written file.
+func writeFile(filep, content string) (err error) {
+	f, err := os.CreateTemp(filepath.Dir(filep), filepath.Base(filep)+".*.tmp")
+	if err != nil {
+		return
+	}
+	tempp := f.Name()
+	defer func() {
+		if err != nil {
+			os.Remove(tempp)
+		}
+	}()
+	if _, err = f.WriteString(content); err != nil {
+		f.Close()
+		return
+	}
+	if err = f.Close(); err != nil {
+		return
+	}
+	if err = os.Chmod(tempp, fileMode(filep)); err != nil {
+		return
+	}
+	return renameFile(tempp, filep)
+}
+
+// Mode of the existing file at filep, or 0644 when there's none.
+func fileMode(filep string) os.FileMode {
+	if info, err := os.Stat(filep); err == nil {
+		return info.Mode().Perm()
+	}
+	return 0644
 }
 
 // Run the snapshot process according to parameters set in [snap.SnapshotSuite.NewSnapshot].
@@ -83,18 +451,83 @@ func (v *Snapshot) write(content string) error {
 // Determining whether any given test fails
 // is left for "equal" function defined in [snap.SnapshotSuite.NewSnapshot].
 func (v *Snapshot) Run(view string) error {
+	_, err := v.run(view)
+	return err
+}
+
+// Run the snapshot and return false if it was verified and didn't match.
+func (v *Snapshot) run(view string) (matched bool, err error) {
 	content, err := v.read()
 	if err != nil {
-		return err
+		return
 	}
 	if v.verify && content != "" {
-		v.equal(content, view, v.Name)
-		return nil
+		if v.readTransform != nil {
+			content = v.readTransform(content)
+		}
+		expected, actual := v.normalize(content), v.normalize(view)
+		matched = expected == actual
+		if v.sidecars {
+			if matched {
+				err = v.removeSidecars()
+			} else {
+				err = v.writeSidecars(view, expected, actual)
+			}
+			if err != nil {
+				return
+			}
+		}
+		v.equal(expected, actual, v.Name)
+		return
 	}
-	if view != content {
-		return v.write(view)
+	matched = true
+	if v.writeTransform != nil {
+		view = v.writeTransform(view)
 	}
-	return nil
+	if view == content {
+		return
+	}
+	if err = v.write(view); err != nil || v.verify || v.report == nil {
+		return
+	}
+	diff, err := unifiedDiff(content, view, "old", "new")
+	if err != nil {
+		return
+	}
+	v.report(v.Name, diff)
+	return
+}
+
+// CaptureOutput calls f and returns what it wrote to stdout and stderr.
+// Both streams are restored when f returns or panics.
+func CaptureOutput(f func()) (stdout, stderr string) {
+	originalStdout, originalStderr := os.Stdout, os.Stderr
+	stdoutWriter, stdoutc := capture()
+	stderrWriter, stderrc := capture()
+	os.Stdout, os.Stderr = stdoutWriter, stderrWriter
+	defer func() {
+		os.Stdout, os.Stderr = originalStdout, originalStderr
+		stdoutWriter.Close()
+		stderrWriter.Close()
+		stdout, stderr = <-stdoutc, <-stderrc
+	}()
+	f()
+	return
+}
+
+// Create a pipe and read it in the background until its writer is closed.
+func capture() (*os.File, <-chan string) {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		panic(err)
+	}
+	c := make(chan string, 1)
+	go func() {
+		defer reader.Close()
+		b, _ := io.ReadAll(reader)
+		c <- string(b)
+	}()
+	return writer, c
 }
 
 // ToSafeFilename replaces all non-safe characters with underscore.
@@ -102,6 +535,21 @@ func ToSafeFilename(s string) string {
 	return nonSafeFilenamePattern.ReplaceAllString(s, "_")
 }
 
+// SeriesConfig configures [snap.RunBubbleTeaSnapshots].
+type SeriesConfig struct {
+	failFast bool
+}
+
+// WithFailFast stops the series at the first frame that doesn't match its snapshot.
+// The mismatching frame is identified in the message passed to the VerifyFunc.
+// When the VerifyFunc doesn't stop the test, e.g. with FailNow,
+// the series is aborted with a panic with the same message.
+func WithFailFast() func(*SeriesConfig) {
+	return func(c *SeriesConfig) {
+		c.failFast = true
+	}
+}
+
 // RunBubbleTeaSnapshots runs snapshots for bubbletea TUIs.
 func RunBubbleTeaSnapshots(
 	snapshotSuite *SnapshotSuite,
@@ -109,21 +557,30 @@ func RunBubbleTeaSnapshots(
 	verify bool,
 	seriesID string,
 	equal VerifyFunc,
+	options ...func(*SeriesConfig),
 ) {
+	config := gent.NewOption(SeriesConfig{}, options...)
 	runSnapshot := func(i int) {
-		snapshot := snapshotSuite.NewSnapshot(
-			fmt.Sprintf("%s_%03d", seriesID, i),
-			verify,
-			equal)
-		if err := snapshot.Run(m.View()); err != nil {
+		name := frameName(seriesID, i)
+		snapshot := snapshotSuite.NewSnapshot(name, verify, equal)
+		mismatch := fmt.Sprintf("Snapshot mismatch at frame %d: %s.", i, name)
+		if config.failFast {
+			// Report the frame before equal gets a chance to end the test.
+			verifyFunc := snapshot.equal
+			snapshot.equal = func(expected, actual, message string) {
+				verifyFunc(expected, actual, gent.Tri(expected == actual, message, mismatch))
+			}
+		}
+		matched, err := snapshot.run(m.View())
+		if err != nil {
 			panic(err)
 		}
+		if config.failFast && !matched {
+			panic(mismatch)
+		}
 	}
 	messageGroups := readMessageGroups(snapshotSuite.rootDir, seriesID)
-	// Quick test elsewhere showed that normal run does init, view, update, and view.
-	cmd := m.Init()
-	m.View()
-	m = runUpdates(m, cmd)
+	m = start(m)
 	runSnapshot(0)
 
 	for i, group := range messageGroups {
@@ -134,6 +591,48 @@ func RunBubbleTeaSnapshots(
 	}
 }
 
+// SnapshotFrames runs a snapshot for each of the views, e.g. frames rendered by a function.
+// Snapshots are named like in [snap.RunBubbleTeaSnapshots], seriesID with the view's index.
+// Error is returned when something unexpected fails, like in [snap.Snapshot.Run].
+func SnapshotFrames(
+	snapshotSuite *SnapshotSuite,
+	seriesID string,
+	views []string,
+	verify bool,
+	equal VerifyFunc,
+	options ...func(*Snapshot),
+) error {
+	for i, each := range views {
+		snapshot := snapshotSuite.NewSnapshot(frameName(seriesID, i), verify, equal, options...)
+		if err := snapshot.Run(each); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func frameName(seriesID string, i int) string {
+	return fmt.Sprintf("%s_%03d", seriesID, i)
+}
+
+// RenderFinal drives m through keys and returns the final view.
+// Keys are like the ones in the message files of [snap.RunBubbleTeaSnapshots].
+// Nothing is read or written, use it for custom assertions.
+func RenderFinal(m tea.Model, keys []string) string {
+	m = start(m)
+	for _, each := range keys {
+		m = runUpdates(m, createKey(each))
+	}
+	return m.View()
+}
+
+func start(m tea.Model) tea.Model {
+	// Quick test elsewhere showed that normal run does init, view, update, and view.
+	cmd := m.Init()
+	m.View()
+	return runUpdates(m, cmd)
+}
+
 func runUpdates(m tea.Model, msg tea.Msg) tea.Model {
 	var cmd tea.Cmd
 	m, cmd = m.Update(msg)
@@ -1,9 +1,22 @@
 package snap
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
 	"testing"
+	"time"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/stretchr/testify/require"
+
+	"github.com/denarced/gent"
 )
 
 func TestSnapshot(t *testing.T) {
@@ -68,3 +81,587 @@ func TestSnapshot(t *testing.T) {
 		},
 	)
 }
+
+func TestSnapshotPathSeparators(t *testing.T) {
+	type param struct {
+		name     string
+		snapshot string
+		view     string
+		option   func(*Snapshot)
+	}
+	run := func(p param) {
+		t.Run(p.name, func(t *testing.T) {
+			req := require.New(t)
+			dirp := t.TempDir()
+			req.Nil(os.WriteFile(filepath.Join(dirp, "paths"), []byte(p.snapshot), 0600))
+
+			var expected, actual string
+			equal := func(e, a, _ string) {
+				expected, actual = e, a
+			}
+			snapshot := NewSnapshotSuite(dirp).NewSnapshot("paths", true, equal, p.option)
+			req.Nil(snapshot.Run(p.view))
+			req.Equal(expected, actual)
+		})
+	}
+
+	run(param{
+		name:     "forward",
+		snapshot: "file: dir/sub/file.txt\n",
+		view:     "file: dir\\sub\\file.txt\n",
+		option:   WithForwardSlashes(),
+	})
+	run(param{
+		name:     "backward",
+		snapshot: "file: dir\\sub\\file.txt\n",
+		view:     "file: dir/sub/file.txt\n",
+		option:   WithBackslashes(),
+	})
+}
+
+func TestNewSnapshotForT(t *testing.T) {
+	dirp := t.TempDir()
+	suite := NewSnapshotSuite(dirp)
+	t.Run("board: 3x3", func(t *testing.T) {
+		req := require.New(t)
+		snapshot := suite.NewSnapshotForT(t, true)
+		req.Equal("TestNewSnapshotForT_board__3x3", snapshot.Name)
+		req.Equal(filepath.Join(dirp, "TestNewSnapshotForT_board__3x3"), snapshot.filep)
+		req.Nil(snapshot.Run("x"))
+		req.Nil(snapshot.Run("x"), "verified against the written snapshot")
+	})
+}
+
+func TestSnapshotWrite(t *testing.T) {
+	noEqual := func(_, _, _ string) {}
+	t.Run("complete", func(t *testing.T) {
+		req := require.New(t)
+		dirp := t.TempDir()
+		snapshot := NewSnapshotSuite(dirp).NewSnapshot("atomic", false, noEqual)
+		req.Nil(snapshot.Run("first"))
+		req.Nil(snapshot.Run("second"))
+
+		b, err := os.ReadFile(filepath.Join(dirp, "atomic"))
+		req.Nil(err)
+		req.Equal("second", string(b))
+		entries, err := os.ReadDir(dirp)
+		req.Nil(err)
+		req.Len(entries, 1, "no temporary files left")
+	})
+
+	t.Run("mode", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("no Unix permissions")
+		}
+		req := require.New(t)
+		dirp := t.TempDir()
+		suite := NewSnapshotSuite(dirp)
+		mode := func(name string) os.FileMode {
+			info, err := os.Stat(filepath.Join(dirp, name))
+			req.Nil(err)
+			return info.Mode().Perm()
+		}
+
+		req.Nil(suite.NewSnapshot("new", false, noEqual).Run("new"))
+		req.Equal(os.FileMode(0644), mode("new"))
+
+		filep := filepath.Join(dirp, "existing")
+		req.Nil(os.WriteFile(filep, []byte("original"), 0600))
+		req.Nil(os.Chmod(filep, 0600))
+		req.Nil(suite.NewSnapshot("existing", false, noEqual).Run("updated"))
+		req.Equal(os.FileMode(0600), mode("existing"), "mode kept")
+	})
+
+	t.Run("failed rename", func(t *testing.T) {
+		req := require.New(t)
+		dirp := t.TempDir()
+		filep := filepath.Join(dirp, "atomic")
+		req.Nil(os.WriteFile(filep, []byte("original"), 0600))
+
+		renameErr := errors.New("interrupted")
+		renameFile = func(_, _ string) error { return renameErr }
+		defer func() { renameFile = os.Rename }()
+
+		snapshot := NewSnapshotSuite(dirp).NewSnapshot("atomic", false, noEqual)
+		req.ErrorIs(snapshot.Run("updated"), renameErr)
+		b, err := os.ReadFile(filep)
+		req.Nil(err)
+		req.Equal("original", string(b))
+		entries, err := os.ReadDir(dirp)
+		req.Nil(err)
+		req.Len(entries, 1, "temporary file removed")
+	})
+}
+
+// Model for tests, collects typed runes and submits them on enter.
+type inputModel struct {
+	input     string
+	submitted []string
+}
+
+func (inputModel) Init() tea.Cmd {
+	return nil
+}
+
+func (v inputModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return v, nil
+	}
+	switch key.Type {
+	case tea.KeyEnter:
+		v.submitted = append(v.submitted, v.input)
+		v.input = ""
+	case tea.KeyRunes:
+		v.input += string(key.Runes)
+	}
+	return v, nil
+}
+
+func (v inputModel) View() string {
+	return strings.Join(v.submitted, ",") + "\n> " + v.input
+}
+
+func TestRenderFinal(t *testing.T) {
+	req := require.New(t)
+	req.Equal("\n> ", RenderFinal(inputModel{}, nil))
+	req.Equal(
+		"ab,c\n> d",
+		RenderFinal(inputModel{}, []string{"a", "b", "enter", "c", "enter", "d"}))
+}
+
+func TestSnapshotMismatchSidecars(t *testing.T) {
+	type param struct {
+		name     string
+		view     string
+		sidecars bool
+		// Sidecars exist from an earlier run.
+		stale bool
+	}
+	run := func(p param) {
+		t.Run(p.name, func(t *testing.T) {
+			req := require.New(t)
+			dirp := t.TempDir()
+			filep := filepath.Join(dirp, "board")
+			req.Nil(os.WriteFile(filep, []byte("x\no\n"), 0600))
+			if p.stale {
+				req.Nil(os.WriteFile(filep+".actual", []byte("old"), 0600))
+				req.Nil(os.WriteFile(filep+".diff", []byte("old"), 0600))
+			}
+
+			snapshot := NewSnapshotSuite(dirp).NewSnapshot(
+				"board",
+				true,
+				func(_, _, _ string) {},
+				WithMismatchSidecars())
+			req.Nil(snapshot.Run(p.view))
+
+			b, err := os.ReadFile(filep)
+			req.Nil(err)
+			req.Equal("x\no\n", string(b), "snapshot untouched")
+			if !p.sidecars {
+				entries, err := os.ReadDir(dirp)
+				req.Nil(err)
+				req.Len(entries, 1, "only the snapshot")
+				return
+			}
+			b, err = os.ReadFile(filep + ".actual")
+			req.Nil(err)
+			req.Equal(p.view, string(b))
+			b, err = os.ReadFile(filep + ".diff")
+			req.Nil(err)
+			req.Contains(string(b), "-o\n+x\n")
+		})
+	}
+
+	run(param{name: "match", view: "x\no\n"})
+	run(param{name: "mismatch", view: "x\nx\n", sidecars: true})
+	run(param{name: "match removes stale", view: "x\no\n", stale: true})
+	run(param{name: "mismatch replaces stale", view: "x\nx\n", sidecars: true, stale: true})
+}
+
+func TestSnapshotGzip(t *testing.T) {
+	req := require.New(t)
+	dirp := t.TempDir()
+	suite := NewSnapshotSuite(dirp)
+	view := strings.Repeat("x o x\n", 100)
+	equal := func(expected, actual, message string) {
+		req.Equal(expected, actual, message)
+	}
+
+	req.Nil(suite.NewSnapshot("large", false, equal, WithGzip()).Run(view))
+	b, err := os.ReadFile(filepath.Join(dirp, "large.gz"))
+	req.Nil(err)
+	req.Less(len(b), len(view), "compressed")
+
+	mismatched := ""
+	verified := suite.NewSnapshot(
+		"large.gz",
+		true,
+		func(expected, actual, _ string) {
+			if expected != actual {
+				mismatched = actual
+			}
+		},
+		WithGzip())
+	req.Nil(verified.Run(view))
+	req.Empty(mismatched, "decompressed snapshot matches")
+	req.Nil(verified.Run("o"))
+	req.Equal("o", mismatched)
+}
+
+func TestIgnoreLinesMatching(t *testing.T) {
+	req := require.New(t)
+	dirp := t.TempDir()
+	req.Nil(os.WriteFile(
+		filepath.Join(dirp, "log"),
+		[]byte("started\n2024-01-01T10:00:00 tick\ndone\n"),
+		0600))
+
+	var expected, actual string
+	snapshot := NewSnapshotSuite(dirp).NewSnapshot(
+		"log",
+		true,
+		func(e, a, _ string) {
+			expected, actual = e, a
+		},
+		IgnoreLinesMatching(
+			regexp.MustCompile(`^\d{4}-\d{2}-\d{2}T`),
+			regexp.MustCompile(`request-id=`)))
+	req.Nil(snapshot.Run(
+		"started\n2025-02-02T11:11:11 tick\nrequest-id=abc\ndone\n"))
+	req.Equal("started\ndone\n", expected)
+	req.Equal(expected, actual)
+}
+
+func TestCaptureOutput(t *testing.T) {
+	t.Run("streams", func(t *testing.T) {
+		req := require.New(t)
+		stdout, stderr := CaptureOutput(func() {
+			fmt.Println("out 1")
+			fmt.Fprintln(os.Stderr, "err 1")
+			fmt.Println("out 2")
+		})
+		req.Equal("out 1\nout 2\n", stdout)
+		req.Equal("err 1\n", stderr)
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		req := require.New(t)
+		originalStdout, originalStderr := os.Stdout, os.Stderr
+		req.PanicsWithValue("boom", func() {
+			CaptureOutput(func() {
+				fmt.Println("before panic")
+				panic("boom")
+			})
+		})
+		req.Same(originalStdout, os.Stdout)
+		req.Same(originalStderr, os.Stderr)
+	})
+}
+
+func TestDefaultVerify(t *testing.T) {
+	req := require.New(t)
+	dirp := t.TempDir()
+	req.Nil(os.WriteFile(filepath.Join(dirp, "default"), []byte("expected"), 0600))
+
+	var mismatches []string
+	suite := NewSnapshotSuite(
+		dirp,
+		WithDefaultVerify(func(expected, actual, message string) {
+			if expected != actual {
+				mismatches = append(mismatches, message)
+			}
+		}))
+	req.Nil(suite.NewSnapshot("default", true, nil).Run("expected"))
+	req.Empty(mismatches)
+	req.Nil(suite.NewSnapshot("default", true, nil).Run("actual"))
+	req.Equal([]string{"default"}, mismatches)
+
+	explicitCalled := false
+	explicit := func(_, _, _ string) { explicitCalled = true }
+	req.Nil(suite.NewSnapshot("default", true, explicit).Run("actual"))
+	req.True(explicitCalled, "explicit VerifyFunc overrides the default")
+	req.Len(mismatches, 1)
+}
+
+func TestSnapshotFrames(t *testing.T) {
+	req := require.New(t)
+	dirp := t.TempDir()
+	suite := NewSnapshotSuite(dirp)
+	views := []string{"frame 0", "frame 1", "frame 2"}
+	equal := func(expected, actual, message string) {
+		req.Equal(expected, actual, message)
+	}
+	req.Nil(SnapshotFrames(suite, "spinner", views, true, equal))
+
+	for i, each := range views {
+		b, err := os.ReadFile(filepath.Join(dirp, fmt.Sprintf("spinner_%03d", i)))
+		req.Nil(err)
+		req.Equal(each, string(b))
+	}
+	entries, err := os.ReadDir(dirp)
+	req.Nil(err)
+	req.Len(entries, len(views))
+	req.Nil(SnapshotFrames(suite, "spinner", views, true, equal), "verified")
+}
+
+func TestWithFilepath(t *testing.T) {
+	req := require.New(t)
+	rootDir, sharedDir := t.TempDir(), t.TempDir()
+	filep := filepath.Join(sharedDir, "fixture")
+	req.Nil(os.WriteFile(filep, []byte("shared"), 0600))
+
+	var expected string
+	snapshot := NewSnapshotSuite(rootDir).NewSnapshot(
+		"named",
+		true,
+		func(e, _, _ string) { expected = e },
+		WithFilepath(filep))
+	req.Nil(snapshot.Run("view"))
+	req.Equal("shared", expected, "read from the override")
+
+	snapshot = NewSnapshotSuite(rootDir).NewSnapshot(
+		"named",
+		false,
+		func(_, _, _ string) {},
+		WithFilepath(filep))
+	req.Nil(snapshot.Run("updated"))
+	b, err := os.ReadFile(filep)
+	req.Nil(err)
+	req.Equal("updated", string(b), "written to the override")
+	entries, err := os.ReadDir(rootDir)
+	req.Nil(err)
+	req.Empty(entries, "nothing in the root directory")
+}
+
+// Clock that returns a fixed time.
+type fixedClock struct {
+	now time.Time
+}
+
+func (v *fixedClock) Now() time.Time {
+	return v.now
+}
+
+func (*fixedClock) After(_ time.Duration) <-chan time.Time {
+	return nil
+}
+
+func TestWithMetadata(t *testing.T) {
+	req := require.New(t)
+	dirp := t.TempDir()
+	readMetadata := func() Metadata {
+		b, err := os.ReadFile(filepath.Join(dirp, "meta.meta"))
+		req.Nil(err)
+		var metadata Metadata
+		req.Nil(json.Unmarshal(b, &metadata))
+		return metadata
+	}
+	clock := &fixedClock{now: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+	run := func(view string) {
+		snapshot := NewSnapshotSuite(dirp).NewSnapshot(
+			"meta",
+			false,
+			func(_, _, _ string) {},
+			WithMetadata(),
+			WithClock(clock))
+		req.Nil(snapshot.Run(view))
+	}
+
+	run("first")
+	first := readMetadata()
+	req.Equal(runtime.Version(), first.GoVersion)
+	req.True(clock.now.Equal(first.Written), "%v", first.Written)
+
+	clock.now = clock.now.Add(time.Hour)
+	run("second")
+	req.True(clock.now.Equal(readMetadata().Written), "updated")
+}
+
+func TestWithUpdateReport(t *testing.T) {
+	req := require.New(t)
+	dirp := t.TempDir()
+	req.Nil(os.WriteFile(filepath.Join(dirp, "report"), []byte("a\nb"), 0600))
+	type report struct {
+		name string
+		diff string
+	}
+	var reports []report
+	run := func(view string, verify bool) {
+		snapshot := NewSnapshotSuite(dirp).NewSnapshot(
+			"report",
+			verify,
+			func(_, _, _ string) {},
+			WithUpdateReport(func(name, diff string) {
+				reports = append(reports, report{name, diff})
+			}))
+		req.Nil(snapshot.Run(view))
+	}
+
+	run("a\nc", false)
+	req.Equal(
+		[]report{{"report", "--- old\n+++ new\n@@ -1,2 +1,2 @@\n a\n-b\n+c\n"}},
+		reports)
+
+	reports = nil
+	run("a\nc", false)
+	req.Empty(reports, "unchanged")
+	run("d", true)
+	req.Empty(reports, "verify mode")
+}
+
+func TestRunBubbleTeaSnapshotsFailFast(t *testing.T) {
+	req := require.New(t)
+	dirp := t.TempDir()
+	req.Nil(os.WriteFile(filepath.Join(dirp, "input.txt"), []byte("a\nb\nc\n"), 0600))
+	suite := NewSnapshotSuite(dirp)
+	RunBubbleTeaSnapshots(suite, inputModel{}, false, "input", func(_, _, _ string) {})
+	req.Nil(os.WriteFile(filepath.Join(dirp, "input_001"), []byte("changed"), 0600))
+
+	verified := []string{}
+	equal := func(_, _, message string) {
+		verified = append(verified, message)
+	}
+	RunBubbleTeaSnapshots(suite, inputModel{}, true, "input", equal)
+	req.Equal([]string{"input_000", "input_001", "input_002", "input_003"}, verified)
+
+	verified = []string{}
+	req.PanicsWithValue(
+		"Snapshot mismatch at frame 1: input_001.",
+		func() {
+			RunBubbleTeaSnapshots(suite, inputModel{}, true, "input", equal, WithFailFast())
+		})
+	req.Equal(
+		[]string{"input_000", "Snapshot mismatch at frame 1: input_001."},
+		verified,
+		"frames after mismatch not run")
+
+	// Like FailNow, stop the goroutine.
+	verified = []string{}
+	fatal := func(expected, actual, message string) {
+		verified = append(verified, message)
+		if expected != actual {
+			runtime.Goexit()
+		}
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		RunBubbleTeaSnapshots(suite, inputModel{}, true, "input", fatal, WithFailFast())
+	}()
+	<-done
+	req.Equal([]string{"input_000", "Snapshot mismatch at frame 1: input_001."}, verified)
+}
+
+func TestReadWriteTransform(t *testing.T) {
+	req := require.New(t)
+	dirp := t.TempDir()
+	filep := filepath.Join(dirp, "lines")
+	sortLines := func(s string) string {
+		lines := strings.Split(s, "\n")
+		sort.Strings(lines)
+		return strings.Join(lines, "\n")
+	}
+	view := "c\na\nb"
+	var expected, actual string
+	newSnapshot := func(verify bool, options ...func(*Snapshot)) *Snapshot {
+		return NewSnapshotSuite(dirp).NewSnapshot(
+			"lines",
+			verify,
+			func(e, a, _ string) { expected, actual = e, a },
+			options...)
+	}
+
+	req.Nil(newSnapshot(false, WithWriteTransform(sortLines)).Run(view))
+	b, err := os.ReadFile(filep)
+	req.Nil(err)
+	req.Equal("a\nb\nc", string(b), "stored transformed")
+
+	req.Nil(newSnapshot(true, WithWriteTransform(sortLines)).Run(view))
+	req.Equal("a\nb\nc", expected)
+	req.Equal(view, actual, "compared untransformed")
+
+	req.Nil(newSnapshot(true, WithReadTransform(strings.ToUpper)).Run(view))
+	req.Equal("A\nB\nC", expected, "read transformed")
+	req.Equal(view, actual)
+}
+
+func TestRemoveEmpty(t *testing.T) {
+	req := require.New(t)
+	dirp := t.TempDir()
+	files := map[string]string{
+		"a":          "",
+		"b":          "b",
+		"c":          "",
+		"d":          "d",
+		".gitkeep":   "",
+		"x.txt":      "",
+		"series.txt": "",
+		"series_000": "frame",
+	}
+	for name, content := range files {
+		req.Nil(os.WriteFile(filepath.Join(dirp, name), []byte(content), 0600))
+	}
+	req.Nil(os.Mkdir(filepath.Join(dirp, "dir"), 0700))
+
+	removed, err := NewSnapshotSuite(dirp).RemoveEmpty()
+	req.Nil(err)
+	req.Equal(3, removed)
+	entries, err := os.ReadDir(dirp)
+	req.Nil(err)
+	req.Equal(
+		[]string{".gitkeep", "b", "d", "dir", "series.txt", "series_000"},
+		gent.Map(entries, func(e os.DirEntry) string { return e.Name() }))
+}
+
+func TestRegionBetween(t *testing.T) {
+	type param struct {
+		name     string
+		view     string
+		expected string
+		actual   string
+	}
+	snapshot := "header 1\n-- begin --\nrow a\nrow b\n-- end --\nfooter 1\n"
+	run := func(p param) {
+		t.Run(p.name, func(t *testing.T) {
+			req := require.New(t)
+			dirp := t.TempDir()
+			req.Nil(os.WriteFile(filepath.Join(dirp, "region"), []byte(snapshot), 0600))
+			var expected, actual string
+			s := NewSnapshotSuite(dirp).NewSnapshot(
+				"region",
+				true,
+				func(e, a, _ string) { expected, actual = e, a },
+				RegionBetween("begin", "end"))
+			req.Nil(s.Run(p.view))
+			req.Equal(p.expected, expected)
+			req.Equal(p.actual, actual)
+		})
+	}
+
+	run(param{
+		name:     "volatile header and footer",
+		view:     "header 2\n-- begin --\nrow a\nrow b\n-- end --\nfooter 2\n",
+		expected: "row a\nrow b",
+		actual:   "row a\nrow b",
+	})
+	run(param{
+		name:     "changed region",
+		view:     "header 1\n-- begin --\nrow a\nrow c\n-- end --\nfooter 1\n",
+		expected: "row a\nrow b",
+		actual:   "row a\nrow c",
+	})
+	run(param{
+		name:     "no end marker",
+		view:     "-- begin --\nrow a\nrow b",
+		expected: "row a\nrow b",
+		actual:   "row a\nrow b",
+	})
+	run(param{
+		name:     "no start marker",
+		view:     "row a\nrow b",
+		expected: "row a\nrow b",
+		actual:   "row a\nrow b",
+	})
+}
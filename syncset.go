@@ -0,0 +1,53 @@
+package gent
+
+import "sync"
+
+// SyncSet is a [gent.Set] that is safe for concurrent use.
+// The zero value is an empty set ready to use.
+type SyncSet[T comparable] struct {
+	mu  sync.RWMutex
+	set Set[T]
+}
+
+// NewSyncSet creates a new [gent.SyncSet].
+func NewSyncSet[T comparable](items ...T) *SyncSet[T] {
+	return &SyncSet[T]{set: *NewSet(items...)}
+}
+
+// Add item to the set, return true if it was added.
+// Otherwise it already existed and wasn't added.
+func (v *SyncSet[T]) Add(item T) bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.set.Add(item)
+}
+
+// Remove removes an item in the set, returns true if it was.
+// I.e. if it existed.
+func (v *SyncSet[T]) Remove(item T) bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.set.Remove(item)
+}
+
+// Has checks if item exists in the set.
+func (v *SyncSet[T]) Has(item T) bool {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.set.Has(item)
+}
+
+// Len returns the number of items in the set.
+func (v *SyncSet[T]) Len() int {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.set.Len()
+}
+
+// ForEachAll iterates all items in the set and calls f for each item.
+// Set is read locked during the iteration so f must not modify the set.
+func (v *SyncSet[T]) ForEachAll(f func(each T)) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	v.set.ForEachAll(f)
+}
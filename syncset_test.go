@@ -0,0 +1,56 @@
+package gent
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncSet(t *testing.T) {
+	t.Run("basics", func(t *testing.T) {
+		req := require.New(t)
+		set := NewSyncSet("a")
+		req.True(set.Add("b"))
+		req.False(set.Add("a"))
+		req.True(set.Has("b"))
+		req.Equal(2, set.Len())
+		req.True(set.Remove("a"))
+		req.False(set.Remove("a"))
+
+		items := []string{}
+		set.ForEachAll(func(each string) {
+			items = append(items, each)
+		})
+		req.Equal([]string{"b"}, items)
+	})
+
+	t.Run("zero value", func(t *testing.T) {
+		req := require.New(t)
+		var set SyncSet[int]
+		req.False(set.Has(1))
+		req.Equal(0, set.Len())
+		req.True(set.Add(1))
+		req.True(set.Has(1))
+		req.Equal(1, set.Len())
+	})
+
+	// Run with -race to verify that there are no data races.
+	t.Run("concurrent", func(t *testing.T) {
+		set := NewSyncSet[int]()
+		var wg sync.WaitGroup
+		for worker := 0; worker < 8; worker++ {
+			wg.Add(1)
+			go func(worker int) {
+				defer wg.Done()
+				for i := 0; i < 100; i++ {
+					set.Add(worker*100 + i)
+					set.Has(i)
+					set.Len()
+				}
+			}(worker)
+		}
+		wg.Wait()
+		require.Equal(t, 800, set.Len())
+	})
+}